@@ -0,0 +1,132 @@
+package clog_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"go.nownabe.dev/clog"
+	"go.nownabe.dev/clog/errors"
+)
+
+func BenchmarkSlogJSONHandler(b *testing.B) {
+	l := slog.New(slog.NewJSONHandler(io.Discard, &slog.HandlerOptions{Level: clog.SeverityInfo}))
+	ctx := context.Background()
+
+	b.Run("Info", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l.Log(ctx, clog.SeverityInfo, "message")
+		}
+	})
+
+	b.Run("InfoWithArgs", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l.Log(ctx, clog.SeverityInfo, "message", "k1", "v1", "k2", 2)
+		}
+	})
+
+	b.Run("LogAttrs", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l.LogAttrs(ctx, clog.SeverityInfo, "message", slog.String("k1", "v1"), slog.Int("k2", 2))
+		}
+	})
+
+	b.Run("Disabled", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l.Log(ctx, clog.SeverityDebug, "message", "k1", "v1")
+		}
+	})
+}
+
+func BenchmarkLogger(b *testing.B) {
+	ctx := context.Background()
+
+	b.Run("Info", func(b *testing.B) {
+		l := clog.New(io.Discard, clog.SeverityInfo, true)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l.Info(ctx, "message")
+		}
+	})
+
+	b.Run("InfoWithArgs", func(b *testing.B) {
+		l := clog.New(io.Discard, clog.SeverityInfo, true)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l.Info(ctx, "message", "k1", "v1", "k2", 2)
+		}
+	})
+
+	b.Run("InfoAttrs", func(b *testing.B) {
+		l := clog.New(io.Discard, clog.SeverityInfo, true)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l.InfoAttrs(ctx, "message", slog.String("k1", "v1"), slog.Int("k2", 2))
+		}
+	})
+
+	b.Run("Infof", func(b *testing.B) {
+		l := clog.New(io.Discard, clog.SeverityInfo, true)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l.Infof(ctx, "message %d", i)
+		}
+	})
+
+	b.Run("Err", func(b *testing.B) {
+		l := clog.New(io.Discard, clog.SeverityInfo, true)
+		err := errors.New("error")
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l.Err(ctx, err)
+		}
+	})
+
+	b.Run("WithoutSourceLocation", func(b *testing.B) {
+		l := clog.New(io.Discard, clog.SeverityInfo, true, clog.WithoutSourceLocation())
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l.InfoAttrs(ctx, "message", slog.String("k1", "v1"), slog.Int("k2", 2))
+		}
+	})
+
+	b.Run("WithLabels", func(b *testing.B) {
+		l := clog.New(io.Discard, clog.SeverityInfo, true, clog.WithLabels(map[string]string{"app": "bench"}))
+		ctx, removeLabel := clog.ContextWithLabel(ctx, "k", "v")
+		defer removeLabel()
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l.Info(ctx, "message")
+		}
+	})
+
+	b.Run("Disabled", func(b *testing.B) {
+		l := clog.New(io.Discard, clog.SeverityInfo, true)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l.Debug(ctx, "message", "k1", "v1")
+		}
+	})
+
+	b.Run("DisabledAttrs", func(b *testing.B) {
+		l := clog.New(io.Discard, clog.SeverityInfo, true)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l.DebugAttrs(ctx, "message", slog.String("k1", "v1"))
+		}
+	})
+
+	b.Run("DisabledErr", func(b *testing.B) {
+		l := clog.New(io.Discard, clog.SeverityInfo, true)
+		err := errors.New("error")
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l.DebugErr(ctx, err)
+		}
+	})
+}
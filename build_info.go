@@ -0,0 +1,116 @@
+package clog
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strconv"
+	"sync"
+)
+
+const startupMessage = "startup"
+
+// startupBuildKeys are the keys of buildLabels emitted in the startup entry in this order.
+var startupBuildKeys = []string{
+	"module", "module_version", "vcs_revision", "vcs_modified", "go_version", "hostname", "pid",
+}
+
+// buildLabels are read once because build info and the process never change.
+var buildLabels = sync.OnceValue(func() map[string]string {
+	labels := map[string]string{
+		"pid": strconv.Itoa(os.Getpid()),
+	}
+
+	if hostname, err := os.Hostname(); err == nil {
+		labels["hostname"] = hostname
+	}
+
+	bi := buildInfo()
+	if bi == nil {
+		return labels
+	}
+
+	labels["go_version"] = bi.GoVersion
+	if bi.Main.Path != "" {
+		labels["module"] = bi.Main.Path
+	}
+	if bi.Main.Version != "" {
+		labels["module_version"] = bi.Main.Version
+	}
+
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			labels["vcs_revision"] = s.Value
+		case "vcs.modified":
+			labels["vcs_modified"] = s.Value
+		}
+	}
+
+	return labels
+})
+
+// WithBuildInfo returns an Option that sets the metadata of the binary and the process as the default labels
+// in the same way as [WithLabels]: the main module path and version, the VCS revision and whether it's modified,
+// the Go version from debug.ReadBuildInfo, the hostname, and the process ID.
+func WithBuildInfo() Option {
+	return WithLabels(buildLabels())
+}
+
+// WithStartupEntry returns an Option that logs a "startup" entry at SeverityNotice when the Logger is created by New.
+// The entry summarizes the configuration of the Logger and the build info, which helps to find
+// which binary with which configuration produced entries.
+func WithStartupEntry() Option {
+	return loggerOption(func(c *loggerConfig) {
+		c.startupEntry = true
+	})
+}
+
+// logStartup logs the startup entry. It must be called by New.
+func (l *Logger) logStartup(s Severity, json bool) {
+	ctx := context.Background()
+	if !l.Enabled(ctx, SeverityNotice) {
+		return
+	}
+
+	format := "text"
+	if json {
+		format = "json"
+	}
+
+	cfg := slog.Group("logger",
+		slog.String("severity", severityString(s)),
+		slog.String("format", format),
+		slog.Bool("source_location", !l.cfg.source.disabled),
+		slog.String("source_path", sourcePathModeString(l.cfg.source.pathMode)),
+		slog.Bool("short_function_name", l.cfg.source.shortFunction),
+		slog.Bool("error_object", l.cfg.errorObject),
+	)
+
+	labels := buildLabels()
+	build := make([]any, 0, len(labels))
+	for _, k := range startupBuildKeys {
+		if v, ok := labels[k]; ok {
+			build = append(build, slog.String(k, v))
+		}
+	}
+
+	// skip [runtime.Callers, callerPC, l.sourceLocation, this function, New]
+	src := l.sourceLocation(5)
+	l.logAttrsWithSource(ctx, SeverityNotice, src, startupMessage, cfg, slog.Group("build", build...))
+}
+
+func sourcePathModeString(m SourcePathMode) string {
+	switch m {
+	case SourcePathFull:
+		return "full"
+	case SourcePathImport:
+		return "import"
+	case SourcePathModule:
+		return "module"
+	case SourcePathGOPATH:
+		return "gopath"
+	}
+
+	return "unknown"
+}
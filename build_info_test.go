@@ -0,0 +1,96 @@
+package clog_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"go.nownabe.dev/clog"
+)
+
+func TestWithBuildInfo(t *testing.T) {
+	t.Parallel()
+
+	l, w := newLogger(clog.SeverityInfo, clog.WithBuildInfo())
+	l.Info(context.Background(), "msg")
+
+	hostname, _ := os.Hostname()
+	labels := map[string]any{
+		"pid":        strconv.Itoa(os.Getpid()),
+		"hostname":   hostname,
+		"go_version": runtime.Version(),
+	}
+	optional := []string{"module", "module_version", "vcs_revision", "vcs_modified"}
+
+	got := map[string]any{}
+	if err := json.Unmarshal(w.Bytes(), &got); err != nil {
+		t.Fatalf("json.Unmarshal() got error: %v", err)
+	}
+	gotLabels, _ := got[keyLabels].(map[string]any)
+	for _, k := range optional {
+		if _, ok := gotLabels[k]; ok {
+			labels[k] = anyString{}
+		}
+	}
+
+	assertEqual(t, buildWantLog("INFO", "msg", keyLabels, labels), got)
+}
+
+func TestWithStartupEntry(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		severity clog.Severity
+		opts     []clog.Option
+		want     map[string]any
+	}{
+		"default": {
+			severity: clog.SeverityInfo,
+			want: buildWantLog("NOTICE", "startup",
+				"logger", map[string]any{
+					"severity":            "INFO",
+					"format":              "json",
+					"source_location":     true,
+					"source_path":         "full",
+					"short_function_name": false,
+					"error_object":        false,
+				},
+				"build", anyNonNil{}),
+		},
+		"configured": {
+			severity: clog.SeverityDebug,
+			opts: []clog.Option{
+				clog.WithSourcePath(clog.SourcePathModule),
+				clog.WithShortFunctionName(),
+				clog.WithErrorObject(),
+			},
+			want: buildWantLog("NOTICE", "startup",
+				"logger", map[string]any{
+					"severity":            "DEBUG",
+					"format":              "json",
+					"source_location":     true,
+					"source_path":         "module",
+					"short_function_name": true,
+					"error_object":        true,
+				},
+				"build", anyNonNil{}),
+		},
+		"disabled": {
+			severity: clog.SeverityWarning,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, w := newLogger(tt.severity, append(tt.opts, clog.WithStartupEntry())...)
+			w.assertLog(t, tt.want)
+			w.assertLog(t, nil)
+		})
+	}
+}
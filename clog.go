@@ -2,7 +2,6 @@ package clog
 
 import (
 	"context"
-	"fmt"
 	"log/slog"
 	"os"
 	"sync/atomic"
@@ -25,12 +24,9 @@ func SetDefault(l *Logger) {
 func SetOptions(opts ...Option) {
 	l := Default()
 
-	h := l.inner.Handler()
-	for _, o := range opts {
-		h = o.apply(h)
-	}
+	h, cfg := applyOptions(l.inner.Handler(), l.cfg, opts)
 
-	SetDefault(&Logger{slog.New(h)})
+	SetDefault(&Logger{slog.New(h), cfg})
 }
 
 // Default returns the default Logger.
@@ -49,7 +45,13 @@ func Debug(ctx context.Context, msg string, args ...any) {
 
 // Debugf logs formatted in the manner of fmt.Printf at SeverityDebug.
 func Debugf(ctx context.Context, format string, a ...any) {
-	Default().log(ctx, SeverityDebug, fmt.Sprintf(format, a...))
+	Default().logf(ctx, SeverityDebug, format, a...)
+}
+
+// DebugAttrs logs at SeverityDebug with the given Attrs.
+// It is more efficient than [Debug] because it doesn't box arguments.
+func DebugAttrs(ctx context.Context, msg string, attrs ...slog.Attr) {
+	Default().logAttrs(ctx, SeverityDebug, msg, attrs...)
 }
 
 // DebugErr logs an error at SeverityDebug.
@@ -64,7 +66,13 @@ func Info(ctx context.Context, msg string, args ...any) {
 
 // Infof logs formatted in the manner of fmt.Printf at SeverityInfo.
 func Infof(ctx context.Context, format string, a ...any) {
-	Default().log(ctx, SeverityInfo, fmt.Sprintf(format, a...))
+	Default().logf(ctx, SeverityInfo, format, a...)
+}
+
+// InfoAttrs logs at SeverityInfo with the given Attrs.
+// It is more efficient than [Info] because it doesn't box arguments.
+func InfoAttrs(ctx context.Context, msg string, attrs ...slog.Attr) {
+	Default().logAttrs(ctx, SeverityInfo, msg, attrs...)
 }
 
 // InfoErr logs an error at SeverityInfo.
@@ -79,7 +87,13 @@ func Notice(ctx context.Context, msg string, args ...any) {
 
 // Noticef logs formatted in the manner of fmt.Printf at SeverityNotice.
 func Noticef(ctx context.Context, format string, a ...any) {
-	Default().log(ctx, SeverityNotice, fmt.Sprintf(format, a...))
+	Default().logf(ctx, SeverityNotice, format, a...)
+}
+
+// NoticeAttrs logs at SeverityNotice with the given Attrs.
+// It is more efficient than [Notice] because it doesn't box arguments.
+func NoticeAttrs(ctx context.Context, msg string, attrs ...slog.Attr) {
+	Default().logAttrs(ctx, SeverityNotice, msg, attrs...)
 }
 
 // NoticeErr logs an error at SeverityNotice.
@@ -94,7 +108,13 @@ func Warning(ctx context.Context, msg string, args ...any) {
 
 // Warningf logs formatted in the manner of fmt.Printf at SeverityWarning.
 func Warningf(ctx context.Context, format string, a ...any) {
-	Default().log(ctx, SeverityWarning, fmt.Sprintf(format, a...))
+	Default().logf(ctx, SeverityWarning, format, a...)
+}
+
+// WarningAttrs logs at SeverityWarning with the given Attrs.
+// It is more efficient than [Warning] because it doesn't box arguments.
+func WarningAttrs(ctx context.Context, msg string, attrs ...slog.Attr) {
+	Default().logAttrs(ctx, SeverityWarning, msg, attrs...)
 }
 
 // WarningErr logs an error at SeverityWarning.
@@ -109,7 +129,13 @@ func Error(ctx context.Context, msg string, args ...any) {
 
 // Errorf logs formatted in the manner of fmt.Printf at SeverityError.
 func Errorf(ctx context.Context, format string, a ...any) {
-	Default().log(ctx, SeverityError, fmt.Sprintf(format, a...))
+	Default().logf(ctx, SeverityError, format, a...)
+}
+
+// ErrorAttrs logs at SeverityError with the given Attrs.
+// It is more efficient than [Error] because it doesn't box arguments.
+func ErrorAttrs(ctx context.Context, msg string, attrs ...slog.Attr) {
+	Default().logAttrs(ctx, SeverityError, msg, attrs...)
 }
 
 // ErrorErr logs an error at SeverityError.
@@ -124,7 +150,13 @@ func Critical(ctx context.Context, msg string, args ...any) {
 
 // Criticalf logs formatted in the manner of fmt.Printf at SeverityCriticaDefault().
 func Criticalf(ctx context.Context, format string, a ...any) {
-	Default().log(ctx, SeverityCritical, fmt.Sprintf(format, a...))
+	Default().logf(ctx, SeverityCritical, format, a...)
+}
+
+// CriticalAttrs logs at SeverityCritical with the given Attrs.
+// It is more efficient than [Critical] because it doesn't box arguments.
+func CriticalAttrs(ctx context.Context, msg string, attrs ...slog.Attr) {
+	Default().logAttrs(ctx, SeverityCritical, msg, attrs...)
 }
 
 // CriticalErr logs an error at SeverityCriticaDefault().
@@ -139,7 +171,13 @@ func Alert(ctx context.Context, msg string, args ...any) {
 
 // Alertf logs formatted in the manner of fmt.Printf at SeverityAlert.
 func Alertf(ctx context.Context, format string, a ...any) {
-	Default().log(ctx, SeverityAlert, fmt.Sprintf(format, a...))
+	Default().logf(ctx, SeverityAlert, format, a...)
+}
+
+// AlertAttrs logs at SeverityAlert with the given Attrs.
+// It is more efficient than [Alert] because it doesn't box arguments.
+func AlertAttrs(ctx context.Context, msg string, attrs ...slog.Attr) {
+	Default().logAttrs(ctx, SeverityAlert, msg, attrs...)
 }
 
 // AlertErr logs an error at SeverityAlert.
@@ -154,7 +192,13 @@ func Emergency(ctx context.Context, msg string, args ...any) {
 
 // Emergencyf logs formatted in the manner of fmt.Printf at SeverityEmergency.
 func Emergencyf(ctx context.Context, format string, a ...any) {
-	Default().log(ctx, SeverityEmergency, fmt.Sprintf(format, a...))
+	Default().logf(ctx, SeverityEmergency, format, a...)
+}
+
+// EmergencyAttrs logs at SeverityEmergency with the given Attrs.
+// It is more efficient than [Emergency] because it doesn't box arguments.
+func EmergencyAttrs(ctx context.Context, msg string, attrs ...slog.Attr) {
+	Default().logAttrs(ctx, SeverityEmergency, msg, attrs...)
 }
 
 // EmergencyErr logs an error at SeverityEmergency.
@@ -167,9 +211,55 @@ func Log(ctx context.Context, s Severity, msg string, args ...any) {
 	Default().log(ctx, s, msg, args...)
 }
 
-// Err is a shorthand for ErrorErr.
+// LogAttrs is a more efficient version of [Log] that accepts only Attrs.
+func LogAttrs(ctx context.Context, s Severity, msg string, attrs ...slog.Attr) {
+	Default().logAttrs(ctx, s, msg, attrs...)
+}
+
+// LogErr logs an error at the highest severity attached by errors.WithSeverity in its wrap chain.
+// If no severity is attached, it is logged at the same severity as [Err].
+func LogErr(ctx context.Context, err error, args ...any) {
+	Default().err(ctx, suggestedSeverity(err), err, args...)
+}
+
+// LogAt emits a log record with the source location of the given program counter instead of the caller's.
+// It is useful for frameworks that log on behalf of user code.
+// pc is a return address like the ones returned by runtime.Callers. See also [FramePC].
+func LogAt(ctx context.Context, pc uintptr, s Severity, msg string, args ...any) {
+	Default().LogAt(ctx, pc, s, msg, args...)
+}
+
+// LogAttrsAt is a more efficient version of [LogAt] that accepts only Attrs.
+func LogAttrsAt(ctx context.Context, pc uintptr, s Severity, msg string, attrs ...slog.Attr) {
+	Default().LogAttrsAt(ctx, pc, s, msg, attrs...)
+}
+
+// ErrAt logs an error with the source location of the given program counter instead of the caller's.
+// See [LogAt].
+func ErrAt(ctx context.Context, pc uintptr, s Severity, err error, args ...any) {
+	Default().ErrAt(ctx, pc, s, err, args...)
+}
+
+// Err logs an error at the default severity of its code of the errors package,
+// e.g. SeverityWarning for NotFound, or at SeverityError if err has no code.
+// It is the same as ErrorErr for errors without codes.
 func Err(ctx context.Context, err error, args ...any) {
-	Default().err(ctx, SeverityError, err, args...)
+	Default().err(ctx, errSeverity(err), err, args...)
+}
+
+// ErrAttrs logs an error with the given Attrs at the same severity as [Err],
+// which is SeverityError unless err has a code.
+// It is more efficient than [Err] because it doesn't box arguments.
+func ErrAttrs(ctx context.Context, err error, attrs ...slog.Attr) {
+	l := Default()
+	s := errSeverity(err)
+	if err == nil || !l.Enabled(ctx, s) {
+		return
+	}
+
+	// skip [runtime.Callers, callerPC, l.sourceLocation, this function]
+	src := l.sourceLocation(4)
+	l.errAttrsWithSource(ctx, s, src, err, attrs...)
 }
 
 // Enabled reports whether the Logger emits log records at the given context and leveDefault().
@@ -195,6 +285,14 @@ func HTTPReq(ctx context.Context, req *HTTPRequest, args ...any) {
 	Default().log(ctx, s, req.msg(), args...)
 }
 
+// WithCallerSkip returns a Logger that skips additional n stack frames
+// to determine logging.googleapis.com/sourceLocation.
+// It is useful for wrapper functions to attribute log entries to their callers.
+// See also [Helper].
+func WithCallerSkip(n int) *Logger {
+	return Default().WithCallerSkip(n)
+}
+
 // WithInsertID returns a Logger that includes the given insertId in each output operation.
 // See https://cloud.google.com/logging/docs/reference/v2/rest/v2/LogEntry
 func WithInsertID(id string) *Logger {
@@ -202,7 +300,17 @@ func WithInsertID(id string) *Logger {
 }
 
 // StartOperation returns a new context and a function to end the opration, starting the operation.
+// The function is the same as [Operation.End] without args. Use [OperationFromContext] to end it with an error.
+// If id is empty, it is generated by the generator set by [WithOperationIDGenerator].
+// Operations started in another operation are nested; they record the parent ID as parent_operation_id
+// and inherit the producer if it is empty.
 // See https://cloud.google.com/logging/docs/reference/v2/rest/v2/LogEntry#LogEntryOperation
 func StartOperation(ctx context.Context, s Severity, msg, id, producer string) (context.Context, func(msg string)) {
 	return Default().startOperation(ctx, s, msg, id, producer)
 }
+
+// Flush emits the log records buffered by the default Logger's handlers such as [WithDedup].
+// Call it before the program exits.
+func Flush(ctx context.Context) error {
+	return Default().Flush(ctx)
+}
@@ -661,7 +661,7 @@ func TestDefaultLogger_Operation(t *testing.T) {
 			keyOperation, map[string]any{"id": "id", "producer": "producer"}))
 	}()
 
-	w.assertLog(t, buildWantLog("INFO", "end",
+	w.assertLog(t, buildWantLog("INFO", "end", "duration", durationRE,
 		keyOperation, map[string]any{"id": "id", "producer": "producer", "last": true}))
 
 	clog.Info(ctx, "msg3")
@@ -741,3 +741,48 @@ func TestDefaultLogger_WithHandleFunc(t *testing.T) {
 	clog.Info(ctx, "msg1")
 	w.assertLog(t, buildWantLog("INFO", "msg1", "user_id", "user1"))
 }
+
+func TestDefaultLogger_AttrsLogFuncs(t *testing.T) {
+	type logFn func(ctx context.Context, msg string, attrs ...slog.Attr)
+
+	tests := map[string]struct {
+		fn             logFn
+		severityString string
+	}{
+		"Debug":     {clog.DebugAttrs, "DEBUG"},
+		"Info":      {clog.InfoAttrs, "INFO"},
+		"Notice":    {clog.NoticeAttrs, "NOTICE"},
+		"Warning":   {clog.WarningAttrs, "WARNING"},
+		"Error":     {clog.ErrorAttrs, "ERROR"},
+		"Critical":  {clog.CriticalAttrs, "CRITICAL"},
+		"Alert":     {clog.AlertAttrs, "ALERT"},
+		"Emergency": {clog.EmergencyAttrs, "EMERGENCY"},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			w := setDefault(clog.SeverityDebug)
+			tt.fn(context.Background(), "msg", slog.String("k1", "v1"))
+			w.assertLog(t, buildWantLog(tt.severityString, "msg", "k1", "v1"))
+		})
+	}
+}
+
+func TestDefaultLogger_LogAttrs(t *testing.T) {
+	w := setDefault(clog.SeverityInfo)
+	clog.LogAttrs(context.Background(), clog.SeverityInfo, "msg", slog.String("k1", "v1"))
+	w.assertLog(t, buildWantLog("INFO", "msg", "k1", "v1"))
+}
+
+func TestDefaultLogger_ErrAttrs(t *testing.T) {
+	w := setDefault(clog.SeverityInfo)
+	clog.ErrAttrs(context.Background(), errors.New("err"), slog.String("k1", "v1"))
+	w.assertLog(t, buildWantLog("ERROR", "err", "k1", "v1", "stack_trace", anyString{}))
+}
+
+func TestDefaultLogger_LogErr(t *testing.T) {
+	w := setDefault(clog.SeverityInfo)
+	clog.LogErr(context.Background(), errors.WithSeverity(errors.NewWithoutStack("err"), clog.SeverityWarning), "k1", "v1")
+	w.assertLog(t, buildWantLog("WARNING", "err", "k1", "v1"))
+}
@@ -0,0 +1,151 @@
+/*
+Package clogtest provides utilities to test code that writes logs with clog.
+
+	logger, rec := clogtest.NewRecorder(clog.SeverityDebug)
+	doSomething(ctx, logger)
+	rs := rec.Records(clogtest.SeverityIs(clog.SeverityError), clogtest.HasLabel("job", "import"))
+*/
+package clogtest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.nownabe.dev/clog"
+)
+
+// Recorder is an in-memory writer that records JSON log entries.
+// It is safe for concurrent use.
+type Recorder struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// NewRecorder returns a new Logger that writes JSON log entries into the returned Recorder.
+func NewRecorder(s clog.Severity, opts ...clog.Option) (*clog.Logger, *Recorder) {
+	r := &Recorder{}
+	return clog.New(r, s, true, opts...), r
+}
+
+// Write implements io.Writer.
+func (r *Recorder) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.buf.Write(p)
+}
+
+// Reset discards all recorded entries.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.buf.Reset()
+}
+
+// Records returns recorded entries that satisfy all of the matchers.
+// It panics if a recorded line is not a valid JSON object.
+func (r *Recorder) Records(ms ...Matcher) []Record {
+	r.mu.Lock()
+	b := bytes.Clone(r.buf.Bytes())
+	r.mu.Unlock()
+
+	var records []Record
+
+	s := bufio.NewScanner(bytes.NewReader(b))
+	s.Buffer(nil, len(b)+1)
+	for s.Scan() {
+		rec, err := ParseRecord(s.Bytes())
+		if err != nil {
+			panic(err)
+		}
+
+		if matchAll(rec, ms) {
+			records = append(records, rec)
+		}
+	}
+
+	return records
+}
+
+// AssertLogged reports an error to tb if no recorded entry satisfies all of the matchers.
+func (r *Recorder) AssertLogged(tb testing.TB, ms ...Matcher) {
+	tb.Helper()
+
+	if len(r.Records(ms...)) == 0 {
+		tb.Errorf("no log entry matched: %s", r.dump())
+	}
+}
+
+// AssertNotLogged reports an error to tb if any recorded entry satisfies all of the matchers.
+func (r *Recorder) AssertNotLogged(tb testing.TB, ms ...Matcher) {
+	tb.Helper()
+
+	if rs := r.Records(ms...); len(rs) > 0 {
+		tb.Errorf("%d log entries matched unexpectedly: %s", len(rs), r.dump())
+	}
+}
+
+func (r *Recorder) dump() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.buf.Len() == 0 {
+		return "(no log entries)"
+	}
+
+	return "\n" + r.buf.String()
+}
+
+// NewTestLogger returns a new Logger that writes log entries to tb.Log.
+// The entries are written in the text format to make them readable in test outputs.
+// Entries logged after the test has finished, e.g. by background goroutines, are discarded
+// because tb.Log panics then.
+func NewTestLogger(tb testing.TB, s clog.Severity, opts ...clog.Option) *clog.Logger {
+	w := &tbWriter{tb: tb}
+	tb.Cleanup(w.finish)
+
+	return clog.New(w, s, false, opts...)
+}
+
+type tbWriter struct {
+	tb testing.TB
+
+	mu   sync.Mutex
+	done bool
+}
+
+func (w *tbWriter) Write(p []byte) (int, error) {
+	w.tb.Helper()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.done {
+		w.tb.Log(strings.TrimSuffix(string(p), "\n"))
+	}
+
+	return len(p), nil
+}
+
+func (w *tbWriter) finish() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.done = true
+}
+
+// ParseRecord parses a JSON log entry.
+func ParseRecord(b []byte) (Record, error) {
+	rec := Record{}
+	if err := json.Unmarshal(b, &rec); err != nil {
+		return nil, fmt.Errorf("clogtest: failed to parse log entry %q: %w", b, err)
+	}
+
+	return rec, nil
+}
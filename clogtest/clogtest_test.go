@@ -0,0 +1,182 @@
+package clogtest_test
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"go.nownabe.dev/clog"
+	"go.nownabe.dev/clog/clogtest"
+	"go.nownabe.dev/clog/errors"
+)
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	l, rec := clogtest.NewRecorder(clog.SeverityInfo, clog.WithLabels(map[string]string{"app": "test"}))
+
+	ctx := context.Background()
+	l.Debug(ctx, "debug")
+	l.Info(ctx, "info", "k1", "v1")
+	l.Err(ctx, errors.New("err"))
+	ctx, end := l.StartOperation(ctx, clog.SeverityNotice, "start", "op1", "producer")
+	end("end")
+	l.HTTPReq(ctx, &clog.HTTPRequest{
+		RequestMethod: "GET", RequestURL: "/path", Status: 200, Latency: 1500 * time.Millisecond,
+	})
+
+	rs := rec.Records()
+	if len(rs) != 5 {
+		t.Fatalf("len(rec.Records()) got %d, want 5: %v", len(rs), rs)
+	}
+
+	if got := rs[0].Severity(); got != clog.SeverityInfo {
+		t.Errorf("rs[0].Severity() got %v, want %v", got, clog.SeverityInfo)
+	}
+	if got := rs[0].Message(); got != "info" {
+		t.Errorf("rs[0].Message() got %q, want %q", got, "info")
+	}
+	if got := rs[0].Labels()["app"]; got != "test" {
+		t.Errorf("rs[0].Labels()[\"app\"] got %q, want %q", got, "test")
+	}
+	if src := rs[0].SourceLocation(); src == nil || src.Function != "go.nownabe.dev/clog/clogtest_test.TestRecorder" {
+		t.Errorf("rs[0].SourceLocation() got %+v", src)
+	}
+	if rs[1].StackTrace() == "" {
+		t.Errorf("rs[1].StackTrace() should not be empty")
+	}
+	if op := rs[2].Operation(); op == nil || op.ID != "op1" || !op.First {
+		t.Errorf("rs[2].Operation() got %+v", op)
+	}
+	wantReq := clogtest.HTTPRequest{
+		RequestMethod: "GET", RequestURL: "/path", Status: 200, Latency: 1500 * time.Millisecond,
+	}
+	if req := rs[4].HTTPRequest(); req == nil || *req != wantReq {
+		t.Errorf("rs[4].HTTPRequest() got %+v, want %+v", req, wantReq)
+	}
+
+	rec.AssertLogged(t, clogtest.SeverityIs(clog.SeverityInfo), clogtest.HasAttr("k1", "v1"))
+	rec.AssertLogged(t, clogtest.SeverityAtLeast(clog.SeverityError), clogtest.HasStackTrace())
+	rec.AssertLogged(t, clogtest.HasOperation("op1"), clogtest.MessageIs("end"))
+	rec.AssertLogged(t, clogtest.MessageMatches(regexp.MustCompile(`^GET`)), clogtest.HasKey("httpRequest"))
+	rec.AssertNotLogged(t, clogtest.MessageIs("debug"))
+	rec.AssertNotLogged(t, clogtest.HasLabel("app", "other"))
+
+	rec.Reset()
+	if rs := rec.Records(); len(rs) != 0 {
+		t.Errorf("len(rec.Records()) got %d after Reset, want 0", len(rs))
+	}
+}
+
+func TestNewTestLogger(t *testing.T) {
+	t.Parallel()
+
+	l := clogtest.NewTestLogger(t, clog.SeverityDebug)
+	l.Info(context.Background(), "logged via t.Log", "key", "value")
+}
+
+// fakeTB records calls of Log and Cleanup.
+type fakeTB struct {
+	testing.TB
+
+	logs     []string
+	cleanups []func()
+}
+
+func (tb *fakeTB) Helper()          {}
+func (tb *fakeTB) Log(args ...any)  { tb.logs = append(tb.logs, fmt.Sprint(args...)) }
+func (tb *fakeTB) Cleanup(f func()) { tb.cleanups = append(tb.cleanups, f) }
+
+func TestNewTestLogger_AfterTest(t *testing.T) {
+	t.Parallel()
+
+	tb := &fakeTB{TB: t}
+	l := clogtest.NewTestLogger(tb, clog.SeverityDebug)
+
+	l.Info(context.Background(), "logged")
+	if len(tb.logs) != 1 {
+		t.Fatalf("tb.Log should be called once, got %q", tb.logs)
+	}
+
+	// Finish the test. tb.Log panics if it's called after that.
+	for _, f := range tb.cleanups {
+		f()
+	}
+
+	l.Info(context.Background(), "discarded")
+	if len(tb.logs) != 1 {
+		t.Errorf("tb.Log should not be called after the test finished, got %q", tb.logs)
+	}
+}
+
+func TestRecorder_AssertGolden(t *testing.T) {
+	t.Parallel()
+
+	l, rec := clogtest.NewRecorder(clog.SeverityInfo)
+
+	ctx, removeLabel := clog.ContextWithLabel(context.Background(), "lk", "lv")
+	defer removeLabel()
+
+	l.Info(ctx, "msg", "k1", "v1")
+	l.Err(ctx, errors.New("err"))
+	err1 := errors.New("err1")
+	err2 := errors.New("err2")
+	l.Err(ctx, errors.Join(err1, err2))
+
+	rec.AssertGolden(t, t.Name())
+}
+
+func TestRecorder_AssertGolden_Dedup(t *testing.T) {
+	t.Parallel()
+
+	l, rec := clogtest.NewRecorder(clog.SeverityInfo, clog.WithDedup(time.Hour))
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		l.Info(ctx, "msg")
+	}
+	if err := l.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	rec.AssertGolden(t, t.Name())
+}
+
+func TestRecorder_AssertGolden_ErrorObject(t *testing.T) {
+	t.Parallel()
+
+	l, rec := clogtest.NewRecorder(clog.SeverityInfo, clog.WithErrorObject())
+
+	l.Err(context.Background(), errors.New("err"))
+
+	rec.AssertGolden(t, t.Name())
+}
+
+func TestRecorder_AssertGolden_Operation(t *testing.T) {
+	t.Parallel()
+
+	l, rec := clogtest.NewRecorder(clog.SeverityInfo)
+
+	ctx, _ := l.StartOperation(context.Background(), clog.SeverityInfo, "start", "id", "producer")
+	clog.OperationFromContext(ctx).Progress(ctx, 1, 2, "half")
+	clog.OperationFromContext(ctx).EndErr(errors.NewWithoutStack("err"), "end")
+
+	rec.AssertGolden(t, t.Name())
+}
+
+func TestRecorder_AssertGolden_UserAttrs(t *testing.T) {
+	t.Parallel()
+
+	l, rec := clogtest.NewRecorder(clog.SeverityInfo)
+
+	// Attributes with the same keys as the ones of clog are kept as they are.
+	l.Info(context.Background(), "msg",
+		"id", int64(12345678901234567),
+		"duration", "1h",
+		"repeated", map[string]any{"first": "a", "last": "b"},
+		"progress", map[string]any{"eta": "1h"})
+
+	rec.AssertGolden(t, t.Name())
+}
@@ -0,0 +1,183 @@
+package clogtest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.nownabe.dev/clog/internal/keys"
+)
+
+const (
+	normalizedTime     = "<time>"
+	normalizedLine     = "<line>"
+	normalizedStack    = "<stack>"
+	normalizedDuration = "<duration>"
+)
+
+// update is registered to the default flag set with the package-specific name
+// not to conflict with flags of test packages, so run `go test -clogtest.update` to update golden files.
+var update = flag.Bool("clogtest.update", false, "update golden files of clogtest")
+
+// Normalize normalizes volatile fields in JSON log entries so that they can be compared deterministically.
+// Each entry is indented with sorted keys, numbers are kept as they are, and following fields are replaced:
+//
+//   - time, and first and last of repeated in summaries of clog.WithDedup are replaced with "<time>".
+//   - duration of ended operations and eta of progress of operations are replaced with "<duration>".
+//   - file of sourceLocation and frames of clog.WithErrorObject is trimmed to the base name,
+//     and line is replaced with "<line>".
+//   - stack_trace and stack_trace of causes are replaced with the error message and "<stack>".
+func Normalize(b []byte) ([]byte, error) {
+	var out bytes.Buffer
+
+	enc := json.NewEncoder(&out)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+
+	s := bufio.NewScanner(bytes.NewReader(b))
+	s.Buffer(nil, len(b)+1)
+	for s.Scan() {
+		// Numbers are kept as they are, while ParseRecord decodes them as float64.
+		rec := Record{}
+		dec := json.NewDecoder(bytes.NewReader(s.Bytes()))
+		dec.UseNumber()
+		if err := dec.Decode(&rec); err != nil {
+			return nil, fmt.Errorf("clogtest: failed to parse log entry %q: %w", s.Bytes(), err)
+		}
+
+		normalizeRecord(rec)
+
+		if err := enc.Encode(rec); err != nil {
+			return nil, err
+		}
+	}
+
+	return out.Bytes(), nil
+}
+
+// normalizeRecord replaces volatile fields. Attributes added by clog itself are normalized
+// only in the shapes that clog emits so that user attributes with the same keys are kept as they are.
+func normalizeRecord(rec Record) {
+	if _, ok := rec["time"]; ok {
+		rec["time"] = normalizedTime
+	}
+
+	if src := rec.GroupValue(keys.SourceLocation); src != nil {
+		normalizeFileLine(src)
+	}
+
+	// Entries of operations have duration at the end and eta in progress.
+	if op := rec.Operation(); op != nil {
+		if _, ok := rec[keys.Duration].(string); ok && op.Last {
+			rec[keys.Duration] = normalizedDuration
+		}
+
+		if progress := rec.GroupValue(keys.Progress); progress != nil {
+			if _, ok := progress["done"]; ok && progress["eta"] != nil {
+				progress["eta"] = normalizedDuration
+			}
+		}
+	}
+
+	// Summary entries of WithDedup have count, first, and last in repeated.
+	if repeated := rec.GroupValue(keys.Repeated); repeated != nil && repeated["count"] != nil {
+		for _, k := range []string{"first", "last"} {
+			if _, ok := repeated[k].(string); ok {
+				repeated[k] = normalizedTime
+			}
+		}
+	}
+
+	// Error objects of WithErrorObject have type, message, and frames.
+	if obj := rec.GroupValue(keys.Error); obj != nil && obj["type"] != nil && obj["message"] != nil {
+		if frames, ok := obj["frames"].([]any); ok {
+			for _, f := range frames {
+				if f, ok := f.(map[string]any); ok {
+					normalizeFileLine(f)
+				}
+			}
+		}
+	}
+
+	if _, ok := rec[keys.StackTrace].(string); !ok {
+		return
+	}
+
+	normalizeStackTrace(rec)
+
+	// causes are emitted along with stack_trace when errors have multiple stacks.
+	if causes, ok := rec[keys.Causes].([]any); ok {
+		for _, c := range causes {
+			if c, ok := c.(map[string]any); ok {
+				normalizeStackTrace(c)
+			}
+		}
+	}
+}
+
+func normalizeFileLine(m map[string]any) {
+	if file, ok := m["file"].(string); ok {
+		m["file"] = path.Base(file)
+	}
+	if _, ok := m["line"]; ok {
+		m["line"] = normalizedLine
+	}
+}
+
+func normalizeStackTrace(m map[string]any) {
+	if st, ok := m[keys.StackTrace].(string); ok {
+		msg, _, _ := strings.Cut(st, "\n\n")
+		m[keys.StackTrace] = msg + "\n\n" + normalizedStack
+	}
+}
+
+// AssertGolden compares the recorded entries normalized by [Normalize] with the golden file testdata/<name>.golden.
+// If the -clogtest.update flag is set, the golden file is overwritten with the recorded entries.
+func (r *Recorder) AssertGolden(tb testing.TB, name string) {
+	tb.Helper()
+
+	r.mu.Lock()
+	b := bytes.Clone(r.buf.Bytes())
+	r.mu.Unlock()
+
+	got, err := Normalize(b)
+	if err != nil {
+		tb.Fatalf("Normalize() got error: %v", err)
+	}
+
+	AssertGolden(tb, name, got)
+}
+
+// AssertGolden compares got with the golden file testdata/<name>.golden.
+// If the -clogtest.update flag is set, the golden file is overwritten with got.
+func AssertGolden(tb testing.TB, name string, got []byte) {
+	tb.Helper()
+
+	file := filepath.Join("testdata", name+".golden")
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			tb.Fatalf("failed to create testdata directory: %v", err)
+		}
+		if err := os.WriteFile(file, got, 0o644); err != nil { //nolint:gosec // Golden files are checked in.
+			tb.Fatalf("failed to update golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(file)
+	if err != nil {
+		tb.Fatalf("failed to read golden file (run with -clogtest.update to create it): %v", err)
+	}
+
+	if !bytes.Equal(got, want) {
+		tb.Errorf("log entries differ from %s (run with -clogtest.update to update it)\ngot:\n%s\nwant:\n%s", file, got, want)
+	}
+}
@@ -0,0 +1,89 @@
+package clogtest
+
+import (
+	"reflect"
+	"regexp"
+
+	"go.nownabe.dev/clog"
+)
+
+// Matcher reports whether a Record satisfies a condition.
+type Matcher func(r Record) bool
+
+// SeverityIs returns a Matcher that matches entries with the severity.
+func SeverityIs(s clog.Severity) Matcher {
+	return func(r Record) bool {
+		return r.Severity() == s
+	}
+}
+
+// SeverityAtLeast returns a Matcher that matches entries with the severity or higher.
+func SeverityAtLeast(s clog.Severity) Matcher {
+	return func(r Record) bool {
+		return r.Severity() >= s
+	}
+}
+
+// MessageIs returns a Matcher that matches entries with the message.
+func MessageIs(msg string) Matcher {
+	return func(r Record) bool {
+		return r.Message() == msg
+	}
+}
+
+// MessageMatches returns a Matcher that matches entries whose message matches re.
+func MessageMatches(re *regexp.Regexp) Matcher {
+	return func(r Record) bool {
+		return re.MatchString(r.Message())
+	}
+}
+
+// HasAttr returns a Matcher that matches entries having the top-level attribute with the value.
+// The value is compared with the JSON-decoded one, so use float64 for numbers and map[string]any for groups.
+func HasAttr(key string, value any) Matcher {
+	return func(r Record) bool {
+		v, ok := r[key]
+		return ok && reflect.DeepEqual(v, value)
+	}
+}
+
+// HasKey returns a Matcher that matches entries having the top-level attribute.
+func HasKey(key string) Matcher {
+	return func(r Record) bool {
+		_, ok := r[key]
+		return ok
+	}
+}
+
+// HasLabel returns a Matcher that matches entries having the label.
+func HasLabel(key, value string) Matcher {
+	return func(r Record) bool {
+		v, ok := r.Labels()[key]
+		return ok && v == value
+	}
+}
+
+// HasOperation returns a Matcher that matches entries in the operation.
+func HasOperation(id string) Matcher {
+	return func(r Record) bool {
+		op := r.Operation()
+		return op != nil && op.ID == id
+	}
+}
+
+// HasStackTrace returns a Matcher that matches entries having a stack trace.
+func HasStackTrace() Matcher {
+	return func(r Record) bool {
+		return r.StackTrace() != ""
+	}
+}
+
+func matchAll(r Record, ms []Matcher) bool {
+	for _, m := range ms {
+		if !m(r) {
+			return false
+		}
+	}
+
+	return true
+}
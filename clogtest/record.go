@@ -0,0 +1,165 @@
+package clogtest
+
+import (
+	"time"
+
+	"go.nownabe.dev/clog"
+	"go.nownabe.dev/clog/internal/keys"
+)
+
+// Record is a parsed JSON log entry.
+// Numbers are decoded as float64 as well as encoding/json.
+type Record map[string]any
+
+// Operation represents logging.googleapis.com/operation.
+type Operation struct {
+	ID       string
+	Producer string
+	First    bool
+	Last     bool
+}
+
+// SourceLocation represents logging.googleapis.com/sourceLocation.
+type SourceLocation struct {
+	File     string
+	Line     string
+	Function string
+}
+
+// HTTPRequest represents the common fields of httpRequest.
+type HTTPRequest struct {
+	RequestMethod string
+	RequestURL    string
+	Status        int
+	Latency       time.Duration
+	UserAgent     string
+	RemoteIP      string
+	Protocol      string
+}
+
+var severities = map[string]clog.Severity{
+	"DEFAULT":   clog.SeverityDefault,
+	"DEBUG":     clog.SeverityDebug,
+	"INFO":      clog.SeverityInfo,
+	"NOTICE":    clog.SeverityNotice,
+	"WARNING":   clog.SeverityWarning,
+	"ERROR":     clog.SeverityError,
+	"CRITICAL":  clog.SeverityCritical,
+	"ALERT":     clog.SeverityAlert,
+	"EMERGENCY": clog.SeverityEmergency,
+}
+
+// Severity returns the severity of the entry.
+// It returns clog.SeverityDefault if the severity is unknown.
+func (r Record) Severity() clog.Severity {
+	return severities[r.StringValue(keys.Severity)]
+}
+
+// Message returns the message of the entry.
+func (r Record) Message() string {
+	return r.StringValue("message")
+}
+
+// Labels returns the labels of the entry.
+func (r Record) Labels() map[string]string {
+	labels := map[string]string{}
+	for k, v := range r.GroupValue(keys.Labels) {
+		if s, ok := v.(string); ok {
+			labels[k] = s
+		}
+	}
+
+	return labels
+}
+
+// Trace returns the trace of the entry like "projects/my-project/traces/xxx".
+func (r Record) Trace() string {
+	return r.StringValue(keys.Trace)
+}
+
+// SpanID returns the span ID of the entry.
+func (r Record) SpanID() string {
+	return r.StringValue(keys.SpanID)
+}
+
+// TraceSampled returns whether the trace of the entry is sampled.
+func (r Record) TraceSampled() bool {
+	b, _ := r[keys.TraceSampled].(bool)
+	return b
+}
+
+// InsertID returns the insertId of the entry.
+func (r Record) InsertID() string {
+	return r.StringValue(keys.InsertID)
+}
+
+// StackTrace returns the stack trace of the entry.
+func (r Record) StackTrace() string {
+	return r.StringValue(keys.StackTrace)
+}
+
+// Operation returns the operation of the entry, or nil if the entry doesn't have an operation.
+func (r Record) Operation() *Operation {
+	g := r.GroupValue(keys.Operation)
+	if g == nil {
+		return nil
+	}
+
+	op := &Operation{}
+	op.ID, _ = g["id"].(string)
+	op.Producer, _ = g["producer"].(string)
+	op.First, _ = g["first"].(bool)
+	op.Last, _ = g["last"].(bool)
+
+	return op
+}
+
+// SourceLocation returns the source location of the entry, or nil if the entry doesn't have a source location.
+func (r Record) SourceLocation() *SourceLocation {
+	g := r.GroupValue(keys.SourceLocation)
+	if g == nil {
+		return nil
+	}
+
+	src := &SourceLocation{}
+	src.File, _ = g["file"].(string)
+	src.Line, _ = g["line"].(string)
+	src.Function, _ = g["function"].(string)
+
+	return src
+}
+
+// HTTPRequest returns the httpRequest of the entry, or nil if the entry doesn't have an httpRequest.
+func (r Record) HTTPRequest() *HTTPRequest {
+	g := r.GroupValue(keys.HTTPRequest)
+	if g == nil {
+		return nil
+	}
+
+	req := &HTTPRequest{}
+	req.RequestMethod, _ = g["requestMethod"].(string)
+	req.RequestURL, _ = g["requestUrl"].(string)
+	req.UserAgent, _ = g["userAgent"].(string)
+	req.RemoteIP, _ = g["remoteIp"].(string)
+	req.Protocol, _ = g["protocol"].(string)
+	if status, ok := g["status"].(float64); ok {
+		req.Status = int(status)
+	}
+	if latency, ok := g["latency"].(string); ok {
+		req.Latency, _ = time.ParseDuration(latency)
+	}
+
+	return req
+}
+
+// StringValue returns the string value of the key, or "" if the value is not a string.
+func (r Record) StringValue(key string) string {
+	s, _ := r[key].(string)
+	return s
+}
+
+// GroupValue returns the object value of the key, or nil if the value is not an object.
+func (r Record) GroupValue(key string) map[string]any {
+	g, _ := r[key].(map[string]any)
+	return g
+}
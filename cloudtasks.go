@@ -0,0 +1,52 @@
+package clog
+
+import (
+	"net/http"
+)
+
+// Labels set by CloudTasksHandler.
+const (
+	cloudTasksQueueNameLabel      = "cloudtasks_queue_name"
+	cloudTasksTaskNameLabel       = "cloudtasks_task_name"
+	cloudTasksRetryCountLabel     = "cloudtasks_task_retry_count"
+	cloudTasksExecutionCountLabel = "cloudtasks_task_execution_count"
+	cloudTasksETALabel            = "cloudtasks_task_eta"
+)
+
+// cloudTasksHeaders maps request headers set by Cloud Tasks to labels.
+// See https://cloud.google.com/tasks/docs/creating-http-target-tasks#handler.
+var cloudTasksHeaders = map[string]string{
+	"X-CloudTasks-QueueName":          cloudTasksQueueNameLabel,
+	"X-CloudTasks-TaskName":           cloudTasksTaskNameLabel,
+	"X-CloudTasks-TaskRetryCount":     cloudTasksRetryCountLabel,
+	"X-CloudTasks-TaskExecutionCount": cloudTasksExecutionCountLabel,
+	"X-CloudTasks-TaskETA":            cloudTasksETALabel,
+}
+
+// CloudTasksHandler returns a middleware for Cloud Tasks HTTP targets.
+// It sets the queue name, the task name, the retry count, the execution count, and the ETA
+// in X-CloudTasks-* headers to labels of the request context.
+// Entries logged with the context share an operation, which is the one propagated by [InjectOperation]
+// in the headers if any, or otherwise the one identified by the task name and the queue name.
+// Neither first nor last entries of the operation are logged.
+// Requests without X-CloudTasks-TaskName are passed through as they are.
+func CloudTasksHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		taskName := r.Header.Get("X-CloudTasks-TaskName")
+		if taskName == "" {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		labels := make(map[string]string, len(cloudTasksHeaders))
+		for header, label := range cloudTasksHeaders {
+			labels[label] = r.Header.Get(header)
+		}
+
+		ctx := contextWithLabels(r.Context(), labels)
+
+		ctx = extractOperationOr(ctx, HeaderCarrier(r.Header), taskName, r.Header.Get("X-CloudTasks-QueueName"))
+
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
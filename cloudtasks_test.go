@@ -0,0 +1,72 @@
+package clog_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.nownabe.dev/clog"
+)
+
+func TestCloudTasksHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		headers map[string]string
+		want    map[string]any
+	}{
+		"task": {
+			headers: map[string]string{
+				"X-CloudTasks-QueueName":          "q",
+				"X-CloudTasks-TaskName":           "t",
+				"X-CloudTasks-TaskRetryCount":     "1",
+				"X-CloudTasks-TaskExecutionCount": "2",
+				"X-CloudTasks-TaskETA":            "1704067200.0",
+			},
+			want: buildWantLog("INFO", "msg",
+				keyLabels, map[string]any{
+					"cloudtasks_queue_name":           "q",
+					"cloudtasks_task_name":            "t",
+					"cloudtasks_task_retry_count":     "1",
+					"cloudtasks_task_execution_count": "2",
+					"cloudtasks_task_eta":             "1704067200.0",
+				},
+				keyOperation, map[string]any{"id": "t", "producer": "q"}),
+		},
+		"propagated operation": {
+			headers: map[string]string{
+				"X-CloudTasks-QueueName":  "q",
+				"X-CloudTasks-TaskName":   "t",
+				"Clog-Operation-Id":       "op",
+				"Clog-Operation-Producer": "producer",
+			},
+			want: buildWantLog("INFO", "msg",
+				keyLabels, map[string]any{"cloudtasks_queue_name": "q", "cloudtasks_task_name": "t"},
+				keyOperation, map[string]any{"id": "op", "producer": "producer"}),
+		},
+		"not task": {
+			want: buildWantLog("INFO", "msg"),
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			l, w := newLogger(clog.SeverityInfo)
+
+			h := clog.CloudTasksHandler(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				l.Info(r.Context(), "msg")
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			w.assertLog(t, tt.want)
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package clog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.nownabe.dev/clog/errors"
+	"go.nownabe.dev/clog/internal/keys"
+)
+
+// WithDedup returns an Option that suppresses identical log records within the window.
+// Records are identical when they have the same severity, message, and source location.
+// The first record is logged as usual, and the repeated ones are collapsed into
+// a summary entry like "msg (repeated 523 times)" when the window closes or on [Logger.Flush].
+func WithDedup(window time.Duration) Option {
+	return optionFunc(func(h slog.Handler) slog.Handler {
+		return &dedupHandler{h, &dedupState{window: window, entries: map[dedupKey]*dedupEntry{}}}
+	})
+}
+
+type dedupKey struct {
+	severity Severity
+	message  string
+	file     string
+	line     string
+}
+
+type dedupEntry struct {
+	ctx     context.Context
+	handler slog.Handler
+	record  slog.Record
+	last    time.Time
+	count   int
+	timer   *time.Timer
+}
+
+type dedupState struct {
+	window time.Duration
+
+	mu      sync.Mutex
+	entries map[dedupKey]*dedupEntry
+}
+
+type dedupHandler struct {
+	slog.Handler
+
+	state *dedupState
+}
+
+func (h *dedupHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.Handler.Enabled(ctx, level)
+}
+
+func (h *dedupHandler) Handle(ctx context.Context, r slog.Record) error {
+	key := newDedupKey(r)
+
+	h.state.mu.Lock()
+	if e, ok := h.state.entries[key]; ok {
+		e.count++
+		e.last = r.Time
+		h.state.mu.Unlock()
+		return nil
+	}
+
+	e := &dedupEntry{
+		ctx:     context.WithoutCancel(ctx),
+		handler: h.Handler,
+		record:  r.Clone(),
+		last:    r.Time,
+	}
+	e.timer = time.AfterFunc(h.state.window, func() { h.state.close(key, e) })
+	h.state.entries[key] = e
+	h.state.mu.Unlock()
+
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *dedupHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &dedupHandler{h.Handler.WithAttrs(attrs), h.state}
+}
+
+func (h *dedupHandler) WithGroup(group string) slog.Handler {
+	return &dedupHandler{h.Handler.WithGroup(group), h.state}
+}
+
+// Flush emits the summary entries of all open windows.
+func (h *dedupHandler) Flush(ctx context.Context) error {
+	h.state.mu.Lock()
+	entries := h.state.entries
+	h.state.entries = map[dedupKey]*dedupEntry{}
+	h.state.mu.Unlock()
+
+	var errs []error
+	for _, e := range entries {
+		e.timer.Stop()
+		errs = append(errs, e.emit())
+	}
+
+	return errors.Join(append(errs, flush(ctx, h.Handler))...)
+}
+
+// close emits the summary entry of e if it's still open.
+// The timer of e may fire after Flush has emitted e and a newer entry has been opened with the same key,
+// so the entry is compared as well as the key.
+func (s *dedupState) close(key dedupKey, e *dedupEntry) {
+	s.mu.Lock()
+	cur, ok := s.entries[key]
+	ok = ok && cur == e
+	if ok {
+		delete(s.entries, key)
+	}
+	s.mu.Unlock()
+
+	if ok {
+		_ = e.emit()
+	}
+}
+
+// emit logs the summary entry if the record was repeated.
+func (e *dedupEntry) emit() error {
+	if e.count == 0 {
+		return nil
+	}
+
+	r := slog.NewRecord(time.Now(), e.record.Level, fmt.Sprintf("%s (repeated %d times)", e.record.Message, e.count), 0)
+	e.record.Attrs(func(a slog.Attr) bool {
+		r.AddAttrs(a)
+		return true
+	})
+	r.AddAttrs(slog.Group(keys.Repeated,
+		slog.Int("count", e.count),
+		slog.Time("first", e.record.Time),
+		slog.Time("last", e.last),
+	))
+
+	return e.handler.Handle(e.ctx, r)
+}
+
+func newDedupKey(r slog.Record) dedupKey {
+	key := dedupKey{severity: r.Level, message: r.Message}
+
+	r.Attrs(func(a slog.Attr) bool {
+		if a.Key != keys.SourceLocation {
+			return true
+		}
+		if src, ok := a.Value.Any().(*sourceLocation); ok && src != nil {
+			key.file = src.file
+			key.line = src.line
+		}
+		return false
+	})
+
+	return key
+}
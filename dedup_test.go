@@ -0,0 +1,86 @@
+package clog_test
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go.nownabe.dev/clog"
+)
+
+func Test_Dedup(t *testing.T) {
+	t.Parallel()
+
+	l, w := newLogger(clog.SeverityInfo, clog.WithDedup(time.Hour))
+
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		l.Info(ctx, "msg1")
+	}
+	l.Info(ctx, "msg2")
+
+	w.assertLog(t, buildWantLog("INFO", "msg1"))
+	w.assertLog(t, buildWantLog("INFO", "msg2"))
+	w.assertLog(t, nil)
+
+	if err := l.Flush(ctx); err != nil {
+		t.Fatalf("l.Flush(ctx) got error %v", err)
+	}
+
+	w.assertLog(t, buildWantLog("INFO", "msg1 (repeated 2 times)",
+		"repeated", map[string]any{"count": 2, "first": timeRE, "last": timeRE}))
+	w.assertLog(t, nil)
+}
+
+// syncWriter is a writer that can be written from handlers' goroutines.
+type syncWriter struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (w *syncWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+// waitLines waits until n lines are written and returns them as a writer.
+func (w *syncWriter) waitLines(t *testing.T, n int) *writer {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		w.mu.Lock()
+		b := bytes.Clone(w.buf.Bytes())
+		w.mu.Unlock()
+
+		if bytes.Count(b, []byte{'\n'}) >= n {
+			return &writer{bytes.NewBuffer(b)}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("%d lines were not written", n)
+	return nil
+}
+
+func Test_Dedup_WindowClosed(t *testing.T) {
+	t.Parallel()
+
+	sw := &syncWriter{}
+	l := clog.New(sw, clog.SeverityInfo, true, clog.WithDedup(10*time.Millisecond))
+
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		l.Warning(ctx, "msg")
+	}
+
+	w := sw.waitLines(t, 2)
+	w.assertLog(t, buildWantLog("WARNING", "msg"))
+	w.assertLog(t, buildWantLog("WARNING", "msg (repeated 1 times)",
+		"repeated", map[string]any{"count": 1, "first": timeRE, "last": timeRE}))
+}
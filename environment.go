@@ -0,0 +1,243 @@
+package clog
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Platforms detected by DetectEnvironment.
+const (
+	PlatformCloudRun       = "cloud_run"
+	PlatformCloudRunJob    = "cloud_run_job"
+	PlatformCloudFunctions = "cloud_functions"
+	PlatformGKE            = "gke"
+	PlatformGCE            = "gce"
+
+	// PlatformKubernetes is detected for Kubernetes clusters other than GKE,
+	// or GKE clusters whose metadata server is unavailable.
+	PlatformKubernetes = "kubernetes"
+)
+
+const (
+	defaultMetadataHost = "metadata.google.internal"
+	metadataTimeout     = 500 * time.Millisecond
+
+	// dmiProductNamePath has "Google Compute Engine" on GCE VMs including GKE nodes.
+	dmiProductNamePath = "/sys/class/dmi/id/product_name"
+)
+
+// metadataHTTPClient is used instead of http.DefaultClient not to go through proxies
+// because the metadata server is link-local.
+var metadataHTTPClient = &http.Client{
+	Transport: &http.Transport{Proxy: nil},
+	Timeout:   metadataTimeout,
+}
+
+// Environment is the runtime environment detected by DetectEnvironment.
+// Fields that aren't available are empty.
+type Environment struct {
+	Platform   string
+	ProjectID  string
+	Region     string
+	Zone       string
+	InstanceID string
+
+	// Cloud Run services and Cloud Functions
+	Service       string
+	Revision      string
+	Configuration string
+	Function      string
+
+	// Cloud Run jobs
+	Job       string
+	Execution string
+	TaskIndex string
+
+	// GKE
+	Cluster   string
+	Namespace string
+	Pod       string
+	Container string
+}
+
+// DetectEnvironment detects the environment from well-known environment variables
+// and the metadata server.
+// The metadata server is queried only if the environment variables indicate Google Cloud,
+// the DMI product name of the machine is Google, or GCE_METADATA_HOST is set,
+// which can also be used to stub the server like "localhost:8080".
+func DetectEnvironment(ctx context.Context) *Environment {
+	env := &Environment{}
+
+	switch {
+	case os.Getenv("FUNCTION_TARGET") != "":
+		env.Platform = PlatformCloudFunctions
+		env.Function = firstNonEmpty(os.Getenv("K_SERVICE"), os.Getenv("FUNCTION_NAME"))
+		env.Revision = os.Getenv("K_REVISION")
+	case os.Getenv("K_SERVICE") != "":
+		env.Platform = PlatformCloudRun
+		env.Service = os.Getenv("K_SERVICE")
+		env.Revision = os.Getenv("K_REVISION")
+		env.Configuration = os.Getenv("K_CONFIGURATION")
+	case os.Getenv("CLOUD_RUN_JOB") != "":
+		env.Platform = PlatformCloudRunJob
+		env.Job = os.Getenv("CLOUD_RUN_JOB")
+		env.Execution = os.Getenv("CLOUD_RUN_EXECUTION")
+		env.TaskIndex = os.Getenv("CLOUD_RUN_TASK_INDEX")
+	case os.Getenv("KUBERNETES_SERVICE_HOST") != "":
+		// It is GKE only if the metadata server is available.
+		env.Platform = PlatformKubernetes
+		env.Namespace = firstNonEmpty(os.Getenv("NAMESPACE_NAME"), os.Getenv("POD_NAMESPACE"))
+		env.Pod = firstNonEmpty(os.Getenv("POD_NAME"), os.Getenv("HOSTNAME"))
+		env.Container = os.Getenv("CONTAINER_NAME")
+	}
+
+	metadataHost := os.Getenv("GCE_METADATA_HOST")
+	if env.Platform == "" && metadataHost == "" && !isGoogleMachine() {
+		return env
+	}
+	if metadataHost == "" {
+		metadataHost = defaultMetadataHost
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, metadataTimeout)
+	defer cancel()
+
+	md := &metadataClient{host: metadataHost}
+
+	env.ProjectID = md.get(ctx, "project/project-id")
+	if env.ProjectID == "" {
+		// The metadata server is unavailable.
+		return env
+	}
+
+	switch env.Platform {
+	case "":
+		env.Platform = PlatformGCE
+	case PlatformKubernetes:
+		env.Platform = PlatformGKE
+	}
+
+	// Cloud Run and Cloud Functions have regions like "projects/123/regions/us-central1",
+	// while GCE and GKE have zones like "projects/123/zones/us-central1-a".
+	if region := md.get(ctx, "instance/region"); region != "" {
+		env.Region = region[strings.LastIndexByte(region, '/')+1:]
+	}
+	if zone := md.get(ctx, "instance/zone"); zone != "" {
+		env.Zone = zone[strings.LastIndexByte(zone, '/')+1:]
+		if env.Region == "" {
+			if i := strings.LastIndexByte(env.Zone, '-'); i > 0 {
+				env.Region = env.Zone[:i]
+			}
+		}
+	}
+
+	env.InstanceID = md.get(ctx, "instance/id")
+
+	if env.Platform == PlatformGKE {
+		env.Cluster = md.get(ctx, "instance/attributes/cluster-name")
+	}
+
+	return env
+}
+
+// Labels returns the non-empty fields of the environment as labels.
+func (e *Environment) Labels() map[string]string {
+	labels := map[string]string{}
+
+	for k, v := range map[string]string{
+		"platform":      e.Platform,
+		"project_id":    e.ProjectID,
+		"region":        e.Region,
+		"zone":          e.Zone,
+		"instance_id":   e.InstanceID,
+		"service":       e.Service,
+		"revision":      e.Revision,
+		"configuration": e.Configuration,
+		"function":      e.Function,
+		"job":           e.Job,
+		"execution":     e.Execution,
+		"task_index":    e.TaskIndex,
+		"cluster":       e.Cluster,
+		"namespace":     e.Namespace,
+		"pod":           e.Pod,
+		"container":     e.Container,
+	} {
+		if v != "" {
+			labels[k] = v
+		}
+	}
+
+	return labels
+}
+
+// WithEnvironmentLabels returns an Option that sets the labels of the environment detected by
+// [DetectEnvironment] as the default labels in the same way as [WithLabels].
+// The environment is detected once when the first entry is logged,
+// so creating the Option doesn't block on the metadata server.
+// Use WithLabels after it to override the labels.
+func WithEnvironmentLabels() Option {
+	labels := sync.OnceValue(func() map[string]string {
+		return DetectEnvironment(context.Background()).Labels()
+	})
+
+	return optionFunc(func(h slog.Handler) slog.Handler {
+		return newDefaultLabelsHandler(h, labels)
+	})
+}
+
+// isGoogleMachine reports whether the machine is a GCE VM by the DMI product name, which is available on Linux.
+func isGoogleMachine() bool {
+	b, err := os.ReadFile(dmiProductNamePath)
+	if err != nil {
+		return false
+	}
+
+	return strings.HasPrefix(strings.TrimSpace(string(b)), "Google")
+}
+
+type metadataClient struct {
+	host string
+}
+
+// get returns the value of the metadata path, or an empty string if it's unavailable.
+// See https://cloud.google.com/compute/docs/metadata/predefined-metadata-keys.
+func (c *metadataClient) get(ctx context.Context, path string) string {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+c.host+"/computeMetadata/v1/"+path, nil)
+	if err != nil {
+		return ""
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	resp, err := metadataHTTPClient.Do(req)
+	if err != nil {
+		return ""
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Metadata-Flavor") != "Google" {
+		return ""
+	}
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(b))
+}
+
+func firstNonEmpty(ss ...string) string {
+	for _, s := range ss {
+		if s != "" {
+			return s
+		}
+	}
+
+	return ""
+}
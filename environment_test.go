@@ -0,0 +1,153 @@
+package clog_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.nownabe.dev/clog"
+)
+
+var environmentVars = []string{
+	"FUNCTION_TARGET", "FUNCTION_NAME", "K_SERVICE", "K_REVISION", "K_CONFIGURATION",
+	"CLOUD_RUN_JOB", "CLOUD_RUN_EXECUTION", "CLOUD_RUN_TASK_INDEX",
+	"KUBERNETES_SERVICE_HOST", "NAMESPACE_NAME", "POD_NAMESPACE", "POD_NAME", "HOSTNAME", "CONTAINER_NAME",
+	"GCE_METADATA_HOST",
+}
+
+func newMetadataServer(t *testing.T, values map[string]string) string {
+	t.Helper()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata-Flavor") != "Google" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		v, ok := values[strings.TrimPrefix(r.URL.Path, "/computeMetadata/v1/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Metadata-Flavor", "Google")
+		_, _ = w.Write([]byte(v))
+	}))
+	t.Cleanup(s.Close)
+
+	return strings.TrimPrefix(s.URL, "http://")
+}
+
+// TestDetectEnvironment isn't parallel because it sets environment variables.
+func TestDetectEnvironment(t *testing.T) {
+	gceMetadata := map[string]string{
+		"project/project-id": "my-project",
+		"instance/zone":      "projects/123/zones/asia-northeast1-a",
+		"instance/id":        "456",
+	}
+
+	tests := map[string]struct {
+		env      map[string]string
+		metadata map[string]string
+		want     map[string]string
+	}{
+		"unknown": {
+			want: map[string]string{},
+		},
+		"Cloud Run": {
+			env: map[string]string{"K_SERVICE": "svc", "K_REVISION": "svc-001", "K_CONFIGURATION": "svc"},
+			metadata: map[string]string{
+				"project/project-id": "my-project",
+				"instance/region":    "projects/123/regions/asia-northeast1",
+				"instance/id":        "456",
+			},
+			want: map[string]string{
+				"platform": "cloud_run", "service": "svc", "revision": "svc-001", "configuration": "svc",
+				"project_id": "my-project", "region": "asia-northeast1", "instance_id": "456",
+			},
+		},
+		"Cloud Run job": {
+			env: map[string]string{"CLOUD_RUN_JOB": "job", "CLOUD_RUN_EXECUTION": "job-abc", "CLOUD_RUN_TASK_INDEX": "0"},
+			want: map[string]string{
+				"platform": "cloud_run_job", "job": "job", "execution": "job-abc", "task_index": "0",
+			},
+		},
+		"Cloud Functions": {
+			env:  map[string]string{"FUNCTION_TARGET": "Handle", "K_SERVICE": "fn", "K_REVISION": "fn-001"},
+			want: map[string]string{"platform": "cloud_functions", "function": "fn", "revision": "fn-001"},
+		},
+		"GKE": {
+			env: map[string]string{
+				"KUBERNETES_SERVICE_HOST": "10.0.0.1", "POD_NAMESPACE": "ns", "POD_NAME": "pod-1", "CONTAINER_NAME": "app",
+			},
+			metadata: map[string]string{
+				"project/project-id":               "my-project",
+				"instance/zone":                    "projects/123/zones/asia-northeast1-a",
+				"instance/attributes/cluster-name": "cluster",
+			},
+			want: map[string]string{
+				"platform": "gke", "namespace": "ns", "pod": "pod-1", "container": "app", "cluster": "cluster",
+				"project_id": "my-project", "region": "asia-northeast1", "zone": "asia-northeast1-a",
+			},
+		},
+		"Kubernetes": {
+			env:  map[string]string{"KUBERNETES_SERVICE_HOST": "10.0.0.1", "POD_NAMESPACE": "ns", "POD_NAME": "pod-1"},
+			want: map[string]string{"platform": "kubernetes", "namespace": "ns", "pod": "pod-1"},
+		},
+		"GCE": {
+			metadata: gceMetadata,
+			want: map[string]string{
+				"platform": "gce", "project_id": "my-project",
+				"region": "asia-northeast1", "zone": "asia-northeast1-a", "instance_id": "456",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			for _, k := range environmentVars {
+				t.Setenv(k, "")
+			}
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			// The stub server without metadata behaves like the unavailable metadata server.
+			t.Setenv("GCE_METADATA_HOST", newMetadataServer(t, tt.metadata))
+
+			got := clog.DetectEnvironment(context.Background()).Labels()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Labels() got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestWithEnvironmentLabels isn't parallel because it sets environment variables.
+func TestWithEnvironmentLabels(t *testing.T) {
+	for _, k := range environmentVars {
+		t.Setenv(k, "")
+	}
+
+	l, w := newLogger(clog.SeverityInfo,
+		clog.WithEnvironmentLabels(),
+		clog.WithLabels(map[string]string{"service": "overridden", "app": "app"}))
+
+	// The environment is detected when the first entry is logged, not when the Option is created.
+	t.Setenv("K_SERVICE", "svc")
+	t.Setenv("K_REVISION", "svc-001")
+	t.Setenv("GCE_METADATA_HOST", newMetadataServer(t, nil))
+
+	l.Info(context.Background(), "msg")
+	w.assertLog(t, buildWantLog("INFO", "msg",
+		keyLabels, map[string]any{"platform": "cloud_run", "service": "overridden", "revision": "svc-001", "app": "app"}))
+
+	// The detected environment is reused.
+	t.Setenv("K_REVISION", "svc-002")
+
+	l.Info(context.Background(), "msg")
+	w.assertLog(t, buildWantLog("INFO", "msg",
+		keyLabels, map[string]any{"platform": "cloud_run", "service": "overridden", "revision": "svc-001", "app": "app"}))
+}
@@ -0,0 +1,65 @@
+package clog
+
+import (
+	"log/slog"
+
+	"go.nownabe.dev/clog/errors"
+)
+
+const (
+	errorCodeKey = "error_code"
+	retriableKey = "retriable"
+)
+
+// errSeverity returns the severity for err logged by Err and ErrAttrs.
+// Errors with codes are logged at the default severities of the codes, and others at SeverityError.
+func errSeverity(err error) Severity {
+	if !errors.HasCode(err) {
+		return SeverityError
+	}
+
+	return codeSeverity(errors.CodeOf(err))
+}
+
+// suggestedSeverity returns the severity for err logged by LogErr.
+func suggestedSeverity(err error) Severity {
+	if s, ok := errors.SeverityOf(err); ok {
+		return s
+	}
+
+	return errSeverity(err)
+}
+
+// codeSeverity returns the default severity of the code.
+// Errors caused by clients and transient errors are at SeverityWarning,
+// while DataLoss is at SeverityCritical.
+func codeSeverity(c errors.Code) Severity {
+	switch c {
+	case errors.OK:
+		return SeverityInfo
+	case errors.Canceled, errors.InvalidArgument, errors.NotFound, errors.AlreadyExists,
+		errors.PermissionDenied, errors.FailedPrecondition, errors.OutOfRange, errors.Unauthenticated,
+		errors.DeadlineExceeded, errors.ResourceExhausted, errors.Aborted, errors.Unavailable:
+		return SeverityWarning
+	case errors.DataLoss:
+		return SeverityCritical
+	case errors.Unknown, errors.Unimplemented, errors.Internal:
+	}
+
+	return SeverityError
+}
+
+// errorCodeAttrs appends error_code if err has a code,
+// and retriable if err has a code or a retriability set by errors.WithRetriable.
+func errorCodeAttrs(attrs []slog.Attr, err error) []slog.Attr {
+	hasCode := errors.HasCode(err)
+	if hasCode {
+		attrs = append(attrs, slog.String(errorCodeKey, errors.CodeOf(err).String()))
+	}
+
+	if _, ok := errors.RetriableOf(err); ok || hasCode {
+		attrs = append(attrs, slog.Bool(retriableKey, errors.IsRetriable(err)))
+	}
+
+	return attrs
+}
@@ -0,0 +1,117 @@
+package clog_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"go.nownabe.dev/clog"
+	"go.nownabe.dev/clog/errors"
+)
+
+func TestLogger_Err_Code(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		err  error
+		want map[string]any
+	}{
+		"without code": {
+			err:  errors.NewWithoutStack("err"),
+			want: buildWantLog("ERROR", "err"),
+		},
+		"NotFound": {
+			err:  errors.WithCode(errors.NewWithoutStack("not found"), errors.NotFound),
+			want: buildWantLog("WARNING", "not found", "error_code", "NotFound", "retriable", false),
+		},
+		"Unavailable": {
+			err:  errors.WithCode(errors.NewWithoutStack("unavailable"), errors.Unavailable),
+			want: buildWantLog("WARNING", "unavailable", "error_code", "Unavailable", "retriable", true),
+		},
+		"Internal": {
+			err:  errors.WithCode(errors.NewWithoutStack("internal"), errors.Internal),
+			want: buildWantLog("ERROR", "internal", "error_code", "Internal", "retriable", false),
+		},
+		"DataLoss": {
+			err:  errors.WithCode(errors.NewWithoutStack("data loss"), errors.DataLoss),
+			want: buildWantLog("CRITICAL", "data loss", "error_code", "DataLoss", "retriable", false),
+		},
+		"WithRetriable without code": {
+			err:  errors.WithRetriable(errors.NewWithoutStack("err"), true),
+			want: buildWantLog("ERROR", "err", "retriable", true),
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			l, w := newLogger(clog.SeverityInfo)
+
+			l.Err(context.Background(), tt.err)
+			w.assertLog(t, tt.want)
+
+			l.ErrAttrs(context.Background(), tt.err)
+			w.assertLog(t, tt.want)
+		})
+	}
+}
+
+func TestLogger_SeverityErr_Code(t *testing.T) {
+	t.Parallel()
+
+	l, w := newLogger(clog.SeverityInfo)
+
+	err := errors.WithCode(errors.NewWithoutStack("not found"), errors.NotFound)
+	l.AlertErr(context.Background(), err, slog.String("k", "v"))
+	w.assertLog(t, buildWantLog("ALERT", "not found", "k", "v", "error_code", "NotFound", "retriable", false))
+}
+
+func TestLogger_LogErr(t *testing.T) {
+	t.Parallel()
+
+	warn := errors.WithSeverity(errors.NewWithoutStack("warn"), clog.SeverityWarning)
+
+	tests := map[string]struct {
+		err  error
+		want map[string]any
+	}{
+		"default": {
+			err:  errors.NewWithoutStack("err"),
+			want: buildWantLog("ERROR", "err"),
+		},
+		"attached": {
+			err:  warn,
+			want: buildWantLog("WARNING", "warn"),
+		},
+		"highest in chain": {
+			err:  errors.WithSeverity(errors.ErrorfWithoutStack("wrap: %w", warn), clog.SeverityNotice),
+			want: buildWantLog("WARNING", "wrap: warn"),
+		},
+		"joined": {
+			err:  errors.Join(warn, errors.WithSeverity(errors.NewWithoutStack("alert"), clog.SeverityAlert)),
+			want: buildWantLog("ALERT", "warn\nalert"),
+		},
+		"code": {
+			err:  errors.WithCode(errors.NewWithoutStack("not found"), errors.NotFound),
+			want: buildWantLog("WARNING", "not found", "error_code", "NotFound", "retriable", false),
+		},
+		"severity over code": {
+			err:  errors.WithSeverity(errors.WithCode(errors.NewWithoutStack("not found"), errors.NotFound), clog.SeverityInfo),
+			want: buildWantLog("INFO", "not found", "error_code", "NotFound", "retriable", false),
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			l, w := newLogger(clog.SeverityInfo)
+			l.LogErr(context.Background(), tt.err, "k", "v")
+			tt.want["k"] = "v"
+			w.assertLog(t, tt.want)
+		})
+	}
+}
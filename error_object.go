@@ -0,0 +1,71 @@
+package clog
+
+import (
+	"fmt"
+	"log/slog"
+
+	"go.nownabe.dev/clog/errors"
+	"go.nownabe.dev/clog/internal/keys"
+)
+
+// WithErrorObject returns an Option that emits a structured "error" object along with stack_trace
+// when errors are logged by Err and its family.
+// The object consists of the type name and the message of the error, the chain of wrapped causes,
+// the names of sentinel errors registered by [errors.RegisterSentinel] that the error matches,
+// and the parsed frames of stack_trace.
+// Wrappers that don't change the message, such as the ones adding stack traces or attributes, are omitted.
+func WithErrorObject() Option {
+	return loggerOption(func(c *loggerConfig) {
+		c.errorObject = true
+	})
+}
+
+type errorObject struct {
+	Type      string         `json:"type"`
+	Message   string         `json:"message"`
+	Causes    []errorLink    `json:"causes,omitempty"`
+	Sentinels []string       `json:"sentinels,omitempty"`
+	Frames    []errors.Frame `json:"frames,omitempty"`
+}
+
+type errorLink struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+func errorObjectAttr(err error, stacks []errors.ErrorWithStack) slog.Attr {
+	var links []errorLink
+
+	errors.Walk(err, func(err error) {
+		if !isTransparentWrapper(err) {
+			links = append(links, errorLink{fmt.Sprintf("%T", err), err.Error()})
+		}
+	})
+
+	obj := errorObject{
+		Type:      fmt.Sprintf("%T", err),
+		Message:   err.Error(),
+		Sentinels: errors.Sentinels(err),
+	}
+	if len(links) > 0 {
+		obj.Type = links[0].Type
+		obj.Causes = links[1:]
+	}
+	if len(stacks) > 0 {
+		obj.Frames = errors.ParseStack(stacks[0].Stack())
+	}
+
+	return slog.Any(keys.Error, obj)
+}
+
+// isTransparentWrapper reports whether err wraps exactly one error with the same message.
+func isTransparentWrapper(err error) bool {
+	u, ok := err.(interface{ Unwrap() error }) //nolint:errorlint
+	if !ok {
+		return false
+	}
+
+	inner := u.Unwrap()
+
+	return inner != nil && inner.Error() == err.Error()
+}
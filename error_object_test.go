@@ -0,0 +1,110 @@
+package clog_test
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.nownabe.dev/clog"
+	"go.nownabe.dev/clog/errors"
+)
+
+type notFoundError struct{ name string }
+
+func (e *notFoundError) Error() string { return e.name + " not found" }
+
+var errClogTestSentinel = errors.NewWithoutStack("sentinel")
+
+func init() {
+	errors.RegisterSentinel("clog_test.errClogTestSentinel", errClogTestSentinel)
+}
+
+func TestWithErrorObject(t *testing.T) {
+	t.Parallel()
+
+	type link struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	}
+	type object struct {
+		Type      string         `json:"type"`
+		Message   string         `json:"message"`
+		Causes    []link         `json:"causes"`
+		Sentinels []string       `json:"sentinels"`
+		Frames    []errors.Frame `json:"frames"`
+	}
+
+	tests := map[string]struct {
+		err        error
+		want       object
+		wantFrames bool
+	}{
+		"without stack": {
+			err:  errors.NewWithoutStack("err"),
+			want: object{Type: "*errors.errorString", Message: "err"},
+		},
+		"with stack": {
+			err:        errors.WithStack(&notFoundError{"user"}),
+			want:       object{Type: "*clog_test.notFoundError", Message: "user not found"},
+			wantFrames: true,
+		},
+		"wrapped": {
+			err: errors.Errorf("wrap: %w", errors.With(&notFoundError{"user"}, "k", "v")),
+			want: object{
+				Type:    "*fmt.wrapError",
+				Message: "wrap: user not found",
+				Causes:  []link{{"*clog_test.notFoundError", "user not found"}},
+			},
+			wantFrames: true,
+		},
+		"sentinel": {
+			err: errors.ErrorfWithoutStack("wrap: %w", errClogTestSentinel),
+			want: object{
+				Type:      "*fmt.wrapError",
+				Message:   "wrap: sentinel",
+				Causes:    []link{{"*errors.errorString", "sentinel"}},
+				Sentinels: []string{"clog_test.errClogTestSentinel"},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			l, w := newLogger(clog.SeverityInfo, clog.WithErrorObject())
+			l.Err(context.Background(), tt.err)
+
+			var got struct {
+				Error object `json:"error"`
+			}
+			if err := json.Unmarshal(w.Bytes(), &got); err != nil {
+				t.Fatalf("json.Unmarshal() got error: %v", err)
+			}
+
+			if tt.wantFrames {
+				if len(got.Error.Frames) == 0 || !strings.HasSuffix(got.Error.Frames[0].Function, "TestWithErrorObject") {
+					t.Errorf("got.Error.Frames got %+v, want frames starting at the test", got.Error.Frames)
+				}
+			} else if len(got.Error.Frames) != 0 {
+				t.Errorf("got.Error.Frames got %+v, want empty", got.Error.Frames)
+			}
+			got.Error.Frames = nil
+
+			if !reflect.DeepEqual(got.Error, tt.want) {
+				t.Errorf("got.Error got %+v, want %+v", got.Error, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithErrorObject_Disabled(t *testing.T) {
+	t.Parallel()
+
+	l, w := newLogger(clog.SeverityInfo)
+	l.Err(context.Background(), errors.NewWithoutStack("err"))
+	w.assertLog(t, buildWantLog("ERROR", "err"))
+}
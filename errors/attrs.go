@@ -0,0 +1,63 @@
+package errors
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// With returns an error that annotates err with the key-value pairs.
+// args are interpreted in the same way as slog.Logger.Log, so slog.Attr can also be used.
+// The pairs are collected from the whole wrap chain by [Attrs] and emitted as attributes by clog.
+// With doesn't add a stack trace. Use [WithStack] as well if needed.
+func With(err error, args ...any) error {
+	if err == nil {
+		return nil
+	}
+
+	return &withAttrs{
+		err:   err,
+		attrs: slog.Group("", args...).Value.Group(),
+	}
+}
+
+// Attrs returns the attributes attached by [With] to err and all errors it wraps,
+// including the ones joined by Join or wrapped by Errorf with multiple %w.
+// When the same key is attached more than once, the outermost one wins.
+func Attrs(err error) []slog.Attr {
+	var attrs []slog.Attr
+	seen := map[string]struct{}{}
+
+	Walk(err, func(err error) {
+		e, ok := err.(*withAttrs) //nolint:errorlint
+		if !ok {
+			return
+		}
+
+		for _, a := range e.attrs {
+			if _, ok := seen[a.Key]; ok {
+				continue
+			}
+			seen[a.Key] = struct{}{}
+			attrs = append(attrs, a)
+		}
+	})
+
+	return attrs
+}
+
+type withAttrs struct {
+	err   error
+	attrs []slog.Attr
+}
+
+func (e *withAttrs) Error() string {
+	return e.err.Error()
+}
+
+func (e *withAttrs) Unwrap() error {
+	return e.err
+}
+
+func (e *withAttrs) Format(s fmt.State, verb rune) {
+	formatWrapper(s, verb, e.err)
+}
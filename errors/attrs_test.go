@@ -0,0 +1,96 @@
+package errors_test
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"go.nownabe.dev/clog/errors"
+)
+
+func TestWith(t *testing.T) {
+	t.Parallel()
+
+	if errors.With(nil, "k", "v") != nil {
+		t.Errorf("errors.With(nil) should be nil")
+	}
+
+	base := errors.New("base")
+	err := errors.With(base, "k1", "v1", slog.Int("k2", 2))
+
+	if err.Error() != "base" {
+		t.Errorf("err.Error() got %q, want %q", err.Error(), "base")
+	}
+
+	if !errors.Is(err, base) {
+		t.Errorf("errors.Is(err, base) should be true")
+	}
+
+	var ews errors.ErrorWithStack
+	if !errors.As(err, &ews) {
+		t.Errorf("errors.As(err, &ews) should be true")
+	}
+
+	if got := fmt.Sprintf("%+v", err); !strings.Contains(got, "goroutine") {
+		t.Errorf("%%+v got %q, should contain stack", got)
+	}
+}
+
+func TestAttrs(t *testing.T) {
+	t.Parallel()
+
+	inner := errors.With(errors.NewWithoutStack("inner"), "user_id", "u1", "k", "inner")
+	other := errors.With(errors.NewWithoutStack("other"), "job_id", "j1")
+	wrapped := errors.ErrorfWithoutStack("wrap: %w", inner)
+	joined := errors.Join(wrapped, other)
+	outer := errors.With(joined, "k", "outer", "request_id", "r1")
+
+	tests := map[string]struct {
+		err  error
+		want []slog.Attr
+	}{
+		"nil": {
+			err:  nil,
+			want: nil,
+		},
+		"without attrs": {
+			err:  errors.NewWithoutStack("foo"),
+			want: nil,
+		},
+		"single": {
+			err:  inner,
+			want: []slog.Attr{slog.String("user_id", "u1"), slog.String("k", "inner")},
+		},
+		"wrapped": {
+			err:  wrapped,
+			want: []slog.Attr{slog.String("user_id", "u1"), slog.String("k", "inner")},
+		},
+		"joined and overridden": {
+			err: outer,
+			want: []slog.Attr{
+				slog.String("k", "outer"),
+				slog.String("request_id", "r1"),
+				slog.String("user_id", "u1"),
+				slog.String("job_id", "j1"),
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := errors.Attrs(tt.err)
+			if len(got) != len(tt.want) {
+				t.Fatalf("len(errors.Attrs(err)) got %d (%v), want %d (%v)", len(got), got, len(tt.want), tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("errors.Attrs(err)[%d] got %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
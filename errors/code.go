@@ -0,0 +1,215 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// Code is a domain code that classifies errors.
+// The values are the same as the canonical gRPC status codes,
+// so they can be converted to google.golang.org/grpc/codes.Code directly.
+// See https://github.com/grpc/grpc/blob/master/doc/statuscodes.md.
+type Code uint32
+
+const (
+	OK                 Code = 0
+	Canceled           Code = 1
+	Unknown            Code = 2
+	InvalidArgument    Code = 3
+	DeadlineExceeded   Code = 4
+	NotFound           Code = 5
+	AlreadyExists      Code = 6
+	PermissionDenied   Code = 7
+	ResourceExhausted  Code = 8
+	FailedPrecondition Code = 9
+	Aborted            Code = 10
+	OutOfRange         Code = 11
+	Unimplemented      Code = 12
+	Internal           Code = 13
+	Unavailable        Code = 14
+	DataLoss           Code = 15
+	Unauthenticated    Code = 16
+)
+
+var codeNames = [...]string{
+	OK:                 "OK",
+	Canceled:           "Canceled",
+	Unknown:            "Unknown",
+	InvalidArgument:    "InvalidArgument",
+	DeadlineExceeded:   "DeadlineExceeded",
+	NotFound:           "NotFound",
+	AlreadyExists:      "AlreadyExists",
+	PermissionDenied:   "PermissionDenied",
+	ResourceExhausted:  "ResourceExhausted",
+	FailedPrecondition: "FailedPrecondition",
+	Aborted:            "Aborted",
+	OutOfRange:         "OutOfRange",
+	Unimplemented:      "Unimplemented",
+	Internal:           "Internal",
+	Unavailable:        "Unavailable",
+	DataLoss:           "DataLoss",
+	Unauthenticated:    "Unauthenticated",
+}
+
+func (c Code) String() string {
+	if int(c) < len(codeNames) {
+		return codeNames[c]
+	}
+
+	return "Code(" + strconv.FormatUint(uint64(c), 10) + ")"
+}
+
+// Retriable reports whether errors with the code are retriable by default.
+// Unavailable, DeadlineExceeded, ResourceExhausted, and Aborted are retriable.
+func (c Code) Retriable() bool {
+	switch c { //nolint:exhaustive
+	case Unavailable, DeadlineExceeded, ResourceExhausted, Aborted:
+		return true
+	default:
+		return false
+	}
+}
+
+// HTTPStatus returns the HTTP status code corresponding to the code
+// in the same way as grpc-gateway.
+func (c Code) HTTPStatus() int {
+	switch c {
+	case OK:
+		return http.StatusOK
+	case Canceled:
+		return 499 // Client Closed Request
+	case InvalidArgument, FailedPrecondition, OutOfRange:
+		return http.StatusBadRequest
+	case DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case NotFound:
+		return http.StatusNotFound
+	case AlreadyExists, Aborted:
+		return http.StatusConflict
+	case PermissionDenied:
+		return http.StatusForbidden
+	case ResourceExhausted:
+		return http.StatusTooManyRequests
+	case Unimplemented:
+		return http.StatusNotImplemented
+	case Unavailable:
+		return http.StatusServiceUnavailable
+	case Unauthenticated:
+		return http.StatusUnauthorized
+	case Unknown, Internal, DataLoss:
+		return http.StatusInternalServerError
+	}
+
+	return http.StatusInternalServerError
+}
+
+// GRPCCode returns the gRPC status code corresponding to the code.
+// Convert it with codes.Code(c.GRPCCode()) to use it with google.golang.org/grpc.
+func (c Code) GRPCCode() uint32 {
+	return uint32(c)
+}
+
+// NewCode returns an error with the code and stack.
+func NewCode(code Code, text string) error {
+	return newWithStack(&withCode{err: errors.New(text), code: code}, 1)
+}
+
+// CodeErrorf returns an error with the code and stack formatted in the same way as Errorf.
+func CodeErrorf(code Code, format string, args ...any) error {
+	err := &withCode{err: fmt.Errorf(format, args...), code: code}
+	if hasStack(args...) {
+		return err
+	}
+
+	return newWithStack(err, 1)
+}
+
+// WithCode annotates err with the code. It doesn't add a stack trace.
+func WithCode(err error, code Code) error {
+	if err == nil {
+		return nil
+	}
+
+	return &withCode{err: err, code: code}
+}
+
+// WithRetriable annotates err with whether it is retriable, which overrides the default of its code.
+func WithRetriable(err error, retriable bool) error {
+	if err == nil {
+		return nil
+	}
+
+	return &withCode{err: err, retriable: &retriable}
+}
+
+// CodeOf returns the outermost code in the wrap chain of err.
+// It returns OK for nil and Unknown for errors without codes.
+func CodeOf(err error) Code {
+	if err == nil {
+		return OK
+	}
+
+	if e := findCode(err, func(e *withCode) bool { return e.retriable == nil }); e != nil {
+		return e.code
+	}
+
+	return Unknown
+}
+
+// HasCode reports whether err or any error it wraps has a code.
+func HasCode(err error) bool {
+	return findCode(err, func(e *withCode) bool { return e.retriable == nil }) != nil
+}
+
+// IsRetriable reports whether err is retriable.
+// The outermost one set by [WithRetriable] is used if any, otherwise the default of [CodeOf] is used.
+func IsRetriable(err error) bool {
+	if retriable, ok := RetriableOf(err); ok {
+		return retriable
+	}
+
+	return CodeOf(err).Retriable()
+}
+
+// RetriableOf returns the outermost retriability set by [WithRetriable] in the wrap chain of err.
+// ok is false if it is not set.
+func RetriableOf(err error) (retriable, ok bool) {
+	if e := findCode(err, func(e *withCode) bool { return e.retriable != nil }); e != nil {
+		return *e.retriable, true
+	}
+
+	return false, false
+}
+
+func findCode(err error, match func(*withCode) bool) *withCode {
+	var found *withCode
+
+	Walk(err, func(err error) {
+		if e, ok := err.(*withCode); ok && found == nil && match(e) { //nolint:errorlint
+			found = e
+		}
+	})
+
+	return found
+}
+
+// withCode holds either a code or a retriability.
+type withCode struct {
+	err       error
+	code      Code
+	retriable *bool
+}
+
+func (e *withCode) Error() string {
+	return e.err.Error()
+}
+
+func (e *withCode) Unwrap() error {
+	return e.err
+}
+
+func (e *withCode) Format(s fmt.State, verb rune) {
+	formatWrapper(s, verb, e.err)
+}
@@ -0,0 +1,139 @@
+package errors_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"go.nownabe.dev/clog/errors"
+)
+
+func TestCode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		code       errors.Code
+		wantString string
+		wantHTTP   int
+		wantRetry  bool
+	}{
+		{errors.OK, "OK", http.StatusOK, false},
+		{errors.Canceled, "Canceled", 499, false},
+		{errors.InvalidArgument, "InvalidArgument", http.StatusBadRequest, false},
+		{errors.DeadlineExceeded, "DeadlineExceeded", http.StatusGatewayTimeout, true},
+		{errors.NotFound, "NotFound", http.StatusNotFound, false},
+		{errors.AlreadyExists, "AlreadyExists", http.StatusConflict, false},
+		{errors.PermissionDenied, "PermissionDenied", http.StatusForbidden, false},
+		{errors.ResourceExhausted, "ResourceExhausted", http.StatusTooManyRequests, true},
+		{errors.Unavailable, "Unavailable", http.StatusServiceUnavailable, true},
+		{errors.Unauthenticated, "Unauthenticated", http.StatusUnauthorized, false},
+		{errors.Internal, "Internal", http.StatusInternalServerError, false},
+		{errors.Code(100), "Code(100)", http.StatusInternalServerError, false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.wantString, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.code.String(); got != tt.wantString {
+				t.Errorf("String() got %q, want %q", got, tt.wantString)
+			}
+			if got := tt.code.HTTPStatus(); got != tt.wantHTTP {
+				t.Errorf("HTTPStatus() got %d, want %d", got, tt.wantHTTP)
+			}
+			if got := tt.code.Retriable(); got != tt.wantRetry {
+				t.Errorf("Retriable() got %t, want %t", got, tt.wantRetry)
+			}
+			if got := tt.code.GRPCCode(); got != uint32(tt.code) {
+				t.Errorf("GRPCCode() got %d, want %d", got, uint32(tt.code))
+			}
+		})
+	}
+}
+
+func TestNewCode(t *testing.T) {
+	t.Parallel()
+
+	notFound := errors.NewCode(errors.NotFound, "user not found")
+	unavailable := errors.NewCode(errors.Unavailable, "db unavailable")
+
+	tests := map[string]struct {
+		err           error
+		wantCode      errors.Code
+		wantHasCode   bool
+		wantRetriable bool
+		wantMsg       string
+	}{
+		"nil":           {nil, errors.OK, false, false, ""},
+		"without code":  {errors.New("foo"), errors.Unknown, false, false, "foo"},
+		"NewCode":       {notFound, errors.NotFound, true, false, "user not found"},
+		"retriable":     {unavailable, errors.Unavailable, true, true, "db unavailable"},
+		"wrapped":       {errors.Errorf("get: %w", notFound), errors.NotFound, true, false, "get: user not found"},
+		"outermost":     {errors.WithCode(notFound, errors.Internal), errors.Internal, true, false, "user not found"},
+		"CodeErrorf":    {errors.CodeErrorf(errors.Aborted, "tx %d", 1), errors.Aborted, true, true, "tx 1"},
+		"WithRetriable": {errors.WithRetriable(unavailable, false), errors.Unavailable, true, false, "db unavailable"},
+		"override":      {errors.WithRetriable(notFound, true), errors.NotFound, true, true, "user not found"},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := errors.CodeOf(tt.err); got != tt.wantCode {
+				t.Errorf("CodeOf() got %v, want %v", got, tt.wantCode)
+			}
+			if got := errors.HasCode(tt.err); got != tt.wantHasCode {
+				t.Errorf("HasCode() got %t, want %t", got, tt.wantHasCode)
+			}
+			if got := errors.IsRetriable(tt.err); got != tt.wantRetriable {
+				t.Errorf("IsRetriable() got %t, want %t", got, tt.wantRetriable)
+			}
+			if tt.err != nil && tt.err.Error() != tt.wantMsg {
+				t.Errorf("Error() got %q, want %q", tt.err.Error(), tt.wantMsg)
+			}
+		})
+	}
+
+	var ews errors.ErrorWithStack
+	if !errors.As(notFound, &ews) {
+		t.Errorf("NewCode() should have stack")
+	}
+	if got := fmt.Sprintf("%+v", errors.WithCode(notFound, errors.Internal)); got == "user not found" {
+		t.Errorf("%%+v of WithCode() should contain stack")
+	}
+	if errors.WithCode(nil, errors.Internal) != nil || errors.WithRetriable(nil, true) != nil {
+		t.Errorf("WithCode(nil) and WithRetriable(nil) should be nil")
+	}
+}
+
+func TestRetriableOf(t *testing.T) {
+	t.Parallel()
+
+	err := errors.NewWithoutStack("err")
+
+	tests := map[string]struct {
+		err           error
+		wantRetriable bool
+		wantOK        bool
+	}{
+		"nil":           {nil, false, false},
+		"without":       {err, false, false},
+		"code only":     {errors.WithCode(err, errors.Unavailable), false, false},
+		"WithRetriable": {errors.WithRetriable(err, true), true, true},
+		"outermost":     {errors.WithRetriable(errors.WithRetriable(err, true), false), false, true},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			retriable, ok := errors.RetriableOf(tt.err)
+			if retriable != tt.wantRetriable || ok != tt.wantOK {
+				t.Errorf("RetriableOf() got (%t, %t), want (%t, %t)", retriable, ok, tt.wantRetriable, tt.wantOK)
+			}
+		})
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
-	"runtime"
 	"strconv"
 )
 
@@ -80,28 +79,91 @@ func WithStack(err error) error {
 	return newWithStack(err, 1)
 }
 
+// Walk calls f for err and all errors it wraps in depth-first order,
+// including the ones joined by Join or wrapped by Errorf with multiple %w.
+func Walk(err error, f func(error)) {
+	if err == nil {
+		return
+	}
+
+	f(err)
+
+	switch x := err.(type) { //nolint:errorlint
+	case interface{ Unwrap() error }:
+		Walk(x.Unwrap(), f)
+	case interface{ Unwrap() []error }:
+		for _, e := range x.Unwrap() {
+			Walk(e, f)
+		}
+	}
+}
+
+// Stacks returns all errors with distinct stacks in the tree of err in depth-first order,
+// including the ones joined by Join or wrapped by Errorf with multiple %w.
+// Only the outermost stack in each wrap chain is returned
+// because the stacks wrapped by it are captured in the same goroutine in most cases,
+// e.g. WithStack over an error that already has a stack.
+func Stacks(err error) []ErrorWithStack {
+	var stacks []ErrorWithStack
+	collectStacks(err, false, &stacks)
+
+	return stacks
+}
+
+func collectStacks(err error, covered bool, stacks *[]ErrorWithStack) {
+	if err == nil {
+		return
+	}
+
+	if ews, ok := err.(ErrorWithStack); ok && !covered { //nolint:errorlint
+		covered = true
+		if !containsStack(*stacks, ews) {
+			*stacks = append(*stacks, ews)
+		}
+	}
+
+	switch x := err.(type) { //nolint:errorlint
+	case interface{ Unwrap() error }:
+		collectStacks(x.Unwrap(), covered, stacks)
+	case interface{ Unwrap() []error }:
+		// Each of multiple errors is a distinct chain.
+		for _, e := range x.Unwrap() {
+			collectStacks(e, false, stacks)
+		}
+	}
+}
+
+func containsStack(stacks []ErrorWithStack, ews ErrorWithStack) bool {
+	for _, s := range stacks {
+		if bytes.Equal(s.Stack(), ews.Stack()) {
+			return true
+		}
+	}
+
+	return false
+}
+
 type withStack struct {
 	err   error
 	stack []byte
 }
 
 func newWithStack(err error, skip int) *withStack {
-	const numFrames = 32
-	pcs := [numFrames]uintptr{}
-
-	// skip [runtime.Callers, this function]
-	n := runtime.Callers(skip+2, pcs[:])
+	// skip [this function]
+	return newWithStackConfig(err, skip+1, defaultStackConfig.Load())
+}
 
+func newWithStackConfig(err error, skip int, cfg *stackConfig) *withStack {
 	buf := bytes.Buffer{}
-	// Use dummy goroutine ID 0.
-	buf.WriteString("goroutine 0 [running]:\n")
-
-	frams := runtime.CallersFrames(pcs[:n])
-	for {
-		f, ok := frams.Next()
-		if !ok {
-			break
-		}
+	if cfg.goroutineID {
+		buf.Write(goroutineHeader())
+	} else {
+		// Use dummy goroutine ID 0.
+		buf.WriteString("goroutine 0 [running]:\n")
+	}
+
+	// skip [runtime.Callers, callers, this function]
+	for _, f := range callers(skip+3, cfg) {
 		buf.WriteString(f.Function)
 		buf.WriteString("(...)\n\t")
 		buf.WriteString(f.File)
@@ -143,6 +205,23 @@ func (e *withStack) Stack() []byte {
 	return e.stack
 }
 
+// formatWrapper formats a wrapper of err which has the same message as err.
+// %+v is delegated to err so that its stack trace is printed if any.
+func formatWrapper(s fmt.State, verb rune, err error) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			fmt.Fprintf(s, "%+v", err)
+		} else {
+			fmt.Fprint(s, err.Error())
+		}
+	case 's':
+		fmt.Fprint(s, err.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", err.Error())
+	}
+}
+
 func hasStack(args ...any) bool {
 	for _, a := range args {
 		if err, ok := a.(error); ok {
@@ -226,3 +226,63 @@ func TestErrorfWithoutStack(t *testing.T) {
 		})
 	}
 }
+
+func TestStacks(t *testing.T) {
+	t.Parallel()
+
+	err1 := errors.New("err1")
+	err2 := errors.New("err2")
+	err3 := errors.NewWithoutStack("err3")
+
+	tests := map[string]struct {
+		err  error
+		want []string
+	}{
+		"nil":           {err: nil, want: nil},
+		"without stack": {err: err3, want: nil},
+		"single":        {err: err1, want: []string{"err1"}},
+		"joined":        {err: errors.Join(err3, err1, err2), want: []string{"err1", "err2"}},
+		"multiple %w":   {err: errors.Errorf("%w %w", err2, err1), want: []string{"err2", "err1"}},
+		"duplicated":    {err: errors.Join(err1, errors.ErrorfWithoutStack("wrap: %w", err1)), want: []string{"err1"}},
+		"nested stacks": {err: errors.WithStack(err1), want: []string{"err1"}},
+		"stack over joined": {
+			err:  errors.WithStack(errors.Join(err1, err2)),
+			want: []string{"err1\nerr2", "err1", "err2"},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := errors.Stacks(tt.err)
+			if len(got) != len(tt.want) {
+				t.Fatalf("len(errors.Stacks(err)) got %d, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if got[i].Error() != tt.want[i] {
+					t.Errorf("errors.Stacks(err)[%d].Error() got %q, want %q", i, got[i].Error(), tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWalk(t *testing.T) {
+	t.Parallel()
+
+	err1 := errors.NewWithoutStack("err1")
+	err2 := errors.NewWithoutStack("err2")
+	err := errors.ErrorfWithoutStack("wrap: %w", errors.Join(err1, err2))
+
+	var got []string
+	errors.Walk(err, func(err error) {
+		got = append(got, err.Error())
+	})
+
+	want := []string{"wrap: err1\nerr2", "err1\nerr2", "err1", "err2"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("errors.Walk got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,35 @@
+package errors
+
+import (
+	"sort"
+	"sync"
+)
+
+var sentinels sync.Map // map[string]error
+
+// RegisterSentinel registers err as a sentinel error named name like "io.EOF".
+// Names of the registered sentinels matching an error by Is are reported by [Sentinels].
+// Registering the same name again replaces the previous one.
+func RegisterSentinel(name string, err error) {
+	sentinels.Store(name, err)
+}
+
+// Sentinels returns the sorted names of the registered sentinel errors that err matches by Is.
+func Sentinels(err error) []string {
+	if err == nil {
+		return nil
+	}
+
+	var names []string
+
+	sentinels.Range(func(k, v any) bool {
+		if Is(err, v.(error)) { //nolint:forcetypeassert
+			names = append(names, k.(string)) //nolint:forcetypeassert
+		}
+		return true
+	})
+
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,42 @@
+package errors_test
+
+import (
+	"io"
+	"reflect"
+	"testing"
+
+	"go.nownabe.dev/clog/errors"
+)
+
+var errSentinelTest = errors.NewWithoutStack("sentinel")
+
+func init() {
+	errors.RegisterSentinel("errors_test.errSentinelTest", errSentinelTest)
+	errors.RegisterSentinel("io.EOF", io.EOF)
+}
+
+func TestSentinels(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		err  error
+		want []string
+	}{
+		"nil":       {nil, nil},
+		"unmatched": {errors.New("foo"), nil},
+		"sentinel":  {errSentinelTest, []string{"errors_test.errSentinelTest"}},
+		"wrapped":   {errors.Errorf("wrap: %w", io.EOF), []string{"io.EOF"}},
+		"joined":    {errors.Join(io.EOF, errSentinelTest), []string{"errors_test.errSentinelTest", "io.EOF"}},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := errors.Sentinels(tt.err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("errors.Sentinels() got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
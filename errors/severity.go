@@ -0,0 +1,52 @@
+package errors
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// WithSeverity annotates err with the suggested severity to log it at, such as clog.SeverityWarning.
+// clog.Logger.LogErr logs errors at the highest severity in their wrap chains.
+// It doesn't add a stack trace.
+func WithSeverity(err error, s slog.Level) error {
+	if err == nil {
+		return nil
+	}
+
+	return &withSeverity{err: err, severity: s}
+}
+
+// SeverityOf returns the highest severity attached by [WithSeverity] to err and all errors it wraps,
+// including the ones joined by Join or wrapped by Errorf with multiple %w.
+// ok is false if no severity is attached.
+func SeverityOf(err error) (s slog.Level, ok bool) {
+	Walk(err, func(err error) {
+		e, isSeverity := err.(*withSeverity) //nolint:errorlint
+		if !isSeverity {
+			return
+		}
+
+		if !ok || e.severity > s {
+			s, ok = e.severity, true
+		}
+	})
+
+	return s, ok
+}
+
+type withSeverity struct {
+	err      error
+	severity slog.Level
+}
+
+func (e *withSeverity) Error() string {
+	return e.err.Error()
+}
+
+func (e *withSeverity) Unwrap() error {
+	return e.err
+}
+
+func (e *withSeverity) Format(s fmt.State, verb rune) {
+	formatWrapper(s, verb, e.err)
+}
@@ -0,0 +1,55 @@
+package errors_test
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"go.nownabe.dev/clog/errors"
+)
+
+func TestSeverityOf(t *testing.T) {
+	t.Parallel()
+
+	warn := errors.WithSeverity(errors.New("warn"), slog.LevelWarn)
+	errLevel := errors.WithSeverity(errors.New("error"), slog.LevelError)
+
+	tests := map[string]struct {
+		err    error
+		want   slog.Level
+		wantOK bool
+	}{
+		"nil":      {nil, 0, false},
+		"none":     {errors.New("foo"), 0, false},
+		"attached": {warn, slog.LevelWarn, true},
+		"wrapped":  {errors.Errorf("wrap: %w", warn), slog.LevelWarn, true},
+		"highest":  {errors.WithSeverity(errLevel, slog.LevelInfo), slog.LevelError, true},
+		"joined":   {errors.Join(warn, errLevel), slog.LevelError, true},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := errors.SeverityOf(tt.err)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("SeverityOf() got (%v, %t), want (%v, %t)", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	if errors.WithSeverity(nil, slog.LevelWarn) != nil {
+		t.Errorf("WithSeverity(nil) should be nil")
+	}
+	if got := warn.Error(); got != "warn" {
+		t.Errorf("Error() got %q, want %q", got, "warn")
+	}
+	if got := fmt.Sprintf("%q %s %v", warn, warn, warn); got != `"warn" warn warn` {
+		t.Errorf("Sprintf() got %q", got)
+	}
+	if got := fmt.Sprintf("%+v", warn); !strings.Contains(got, "goroutine") {
+		t.Errorf("%%+v of WithSeverity() should contain stack, got %q", got)
+	}
+}
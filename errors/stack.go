@@ -0,0 +1,222 @@
+package errors
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+const defaultStackDepth = 32
+
+// StackOption configures how stacks are captured.
+type StackOption func(c *stackConfig)
+
+// StackDepth returns a StackOption that sets the maximum number of frames in a stack.
+// The default is 32.
+func StackDepth(n int) StackOption {
+	return func(c *stackConfig) {
+		c.depth = n
+	}
+}
+
+// SkipFrames returns a StackOption that drops frames whose function names start with any of the prefixes
+// like "net/http." or "testing.".
+// Dropped frames are not counted in the depth. runtime.goexit is always dropped.
+func SkipFrames(prefixes ...string) StackOption {
+	return func(c *stackConfig) {
+		c.skipPrefixes = append(c.skipPrefixes, prefixes...)
+	}
+}
+
+// GoroutineID returns a StackOption that writes the actual ID of the capturing goroutine
+// in the header of stacks like "goroutine 42 [running]:" instead of the dummy ID 0.
+// It costs an extra call of runtime.Stack.
+func GoroutineID() StackOption {
+	return func(c *stackConfig) {
+		c.goroutineID = true
+	}
+}
+
+// SetStackOptions sets the default StackOptions used by functions that capture stacks such as New and Errorf.
+// It replaces the previous default ones.
+func SetStackOptions(opts ...StackOption) {
+	defaultStackConfig.Store(newStackConfig(opts))
+}
+
+// WithStackOptions wraps the given error with stack captured with the options instead of the default ones.
+func WithStackOptions(err error, opts ...StackOption) error {
+	if err == nil {
+		return nil
+	}
+
+	return newWithStackConfig(err, 1, newStackConfig(opts))
+}
+
+type stackConfig struct {
+	depth        int
+	skipPrefixes []string
+	goroutineID  bool
+}
+
+var defaultStackConfig atomic.Pointer[stackConfig]
+
+func init() {
+	defaultStackConfig.Store(newStackConfig(nil))
+}
+
+func newStackConfig(opts []StackOption) *stackConfig {
+	c := &stackConfig{depth: defaultStackDepth, skipPrefixes: []string{"runtime.goexit"}}
+	for _, o := range opts {
+		o(c)
+	}
+
+	return c
+}
+
+func (c *stackConfig) skips(f runtime.Frame) bool {
+	for _, p := range c.skipPrefixes {
+		if strings.HasPrefix(f.Function, p) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// goroutineHeader returns the header line of the current goroutine's stack like "goroutine 42 [running]:\n".
+func goroutineHeader() []byte {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+
+	if i := bytes.IndexByte(buf[:n], '\n'); i >= 0 {
+		return buf[:i+1]
+	}
+
+	return []byte("goroutine 0 [running]:\n")
+}
+
+// callers returns at most cfg.depth frames of the caller's stack that are not skipped by cfg.
+func callers(skip int, cfg *stackConfig) []runtime.Frame {
+	if cfg.depth <= 0 {
+		return nil
+	}
+
+	// Capture extra frames to fill the depth after skipping frames.
+	pcs := make([]uintptr, cfg.depth+defaultStackDepth)
+
+	n := runtime.Callers(skip, pcs)
+
+	frames := make([]runtime.Frame, 0, cfg.depth)
+
+	fs := runtime.CallersFrames(pcs[:n])
+	for len(frames) < cfg.depth {
+		f, more := fs.Next()
+		if !cfg.skips(f) && f.Function != "" {
+			frames = append(frames, f)
+		}
+		if !more {
+			break
+		}
+	}
+
+	return frames
+}
+
+// FromPanic returns an error with the full output of runtime.Stack for the value recovered from a panic.
+// Unlike other stacks in this package, the stack has the actual goroutine ID, arguments, and frames of the panic,
+// and the error message is formatted like "panic: <value>" so that Error Reporting recognizes it as a Go panic.
+// It should be called in the deferred function calling recover.
+//
+//	defer func() {
+//		if r := recover(); r != nil {
+//			clog.Err(ctx, errors.FromPanic(r))
+//		}
+//	}()
+func FromPanic(v any) error {
+	if v == nil {
+		return nil
+	}
+
+	err, ok := v.(error)
+	if !ok {
+		err = fmt.Errorf("%v", v)
+	}
+
+	return &withStack{
+		err:   &panicError{err: err},
+		stack: fullStack(),
+	}
+}
+
+type panicError struct {
+	err error
+}
+
+func (e *panicError) Error() string {
+	return "panic: " + e.err.Error()
+}
+
+func (e *panicError) Unwrap() error {
+	return e.err
+}
+
+// fullStack returns runtime.Stack of the current goroutine, growing the buffer until it fits.
+func fullStack() []byte {
+	buf := make([]byte, 4096)
+	for {
+		n := runtime.Stack(buf, false)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, len(buf)*2)
+	}
+}
+
+// Frame is a stack frame parsed by [ParseStack].
+type Frame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// ParseStack parses stack in the format of runtime.Stack into frames.
+// Arguments, program counter offsets, and goroutine headers are dropped.
+// Frames of "created by" lines are included as well.
+func ParseStack(stack []byte) []Frame {
+	var frames []Frame
+
+	lines := strings.Split(string(stack), "\n")
+	for i := 0; i+1 < len(lines); i++ {
+		fn, loc := lines[i], lines[i+1]
+		if !strings.HasPrefix(loc, "\t") || strings.HasPrefix(fn, "\t") {
+			continue
+		}
+
+		if rest, ok := strings.CutPrefix(fn, "created by "); ok {
+			fn, _, _ = strings.Cut(rest, " in goroutine ")
+		} else if j := strings.LastIndexByte(fn, '('); j > 0 && strings.HasSuffix(fn, ")") {
+			fn = fn[:j]
+		}
+
+		// Drop the program counter offset like " +0x1d".
+		loc, _, _ = strings.Cut(loc[1:], " ")
+
+		j := strings.LastIndexByte(loc, ':')
+		if j < 0 {
+			continue
+		}
+
+		line, err := strconv.Atoi(loc[j+1:])
+		if err != nil {
+			continue
+		}
+
+		frames = append(frames, Frame{Function: fn, File: loc[:j], Line: line})
+		i++
+	}
+
+	return frames
+}
@@ -0,0 +1,185 @@
+package errors_test
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"go.nownabe.dev/clog/errors"
+)
+
+func stackOf(t *testing.T, err error) []byte {
+	t.Helper()
+
+	var ews errors.ErrorWithStack
+	if !errors.As(err, &ews) {
+		t.Fatalf("errors.As(err, &ews) should be true")
+	}
+
+	return ews.Stack()
+}
+
+func TestWithStackOptions(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		opts      []errors.StackOption
+		wantLines int
+		want      []string
+		notWant   []string
+	}{
+		"default": {
+			wantLines: 6,
+			want:      []string{"errors_test.TestWithStackOptions", "testing.tRunner"},
+			notWant:   []string{"runtime.goexit"},
+		},
+		"depth": {
+			opts:      []errors.StackOption{errors.StackDepth(1)},
+			wantLines: 4,
+			want:      []string{"errors_test.TestWithStackOptions"},
+			notWant:   []string{"testing.tRunner"},
+		},
+		"zero depth": {
+			opts:      []errors.StackOption{errors.StackDepth(0)},
+			wantLines: 2,
+			notWant:   []string{"errors_test.TestWithStackOptions"},
+		},
+		"skip": {
+			opts:      []errors.StackOption{errors.SkipFrames("testing.")},
+			wantLines: 4,
+			want:      []string{"errors_test.TestWithStackOptions"},
+			notWant:   []string{"testing.tRunner"},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := errors.WithStackOptions(errors.NewWithoutStack("foo"), tt.opts...)
+			stack := string(stackOf(t, err))
+
+			if lines := strings.Split(stack, "\n"); len(lines) != tt.wantLines {
+				t.Errorf("len(lines) got %d (%#v), want %d", len(lines), lines, tt.wantLines)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(stack, w) {
+					t.Errorf("stack should contain %q:\n%s", w, stack)
+				}
+			}
+			for _, w := range tt.notWant {
+				if strings.Contains(stack, w) {
+					t.Errorf("stack should not contain %q:\n%s", w, stack)
+				}
+			}
+		})
+	}
+
+	if err := errors.WithStackOptions(nil); err != nil {
+		t.Errorf("errors.WithStackOptions(nil) got %v, want nil", err)
+	}
+}
+
+// TestSetStackOptions isn't parallel because it changes the default options.
+func TestSetStackOptions(t *testing.T) {
+	errors.SetStackOptions(errors.SkipFrames("testing."))
+	defer errors.SetStackOptions()
+
+	stack := string(stackOf(t, errors.New("foo")))
+	if strings.Contains(stack, "testing.tRunner") {
+		t.Errorf("stack should not contain testing.tRunner:\n%s", stack)
+	}
+	if !strings.Contains(stack, "errors_test.TestSetStackOptions") {
+		t.Errorf("stack should contain errors_test.TestSetStackOptions:\n%s", stack)
+	}
+}
+
+func TestGoroutineID(t *testing.T) {
+	t.Parallel()
+
+	stack := string(stackOf(t, errors.WithStackOptions(errors.NewWithoutStack("foo"), errors.GoroutineID())))
+
+	header, _, _ := strings.Cut(stack, "\n")
+	if !regexp.MustCompile(`^goroutine [1-9][0-9]* \[running\]:$`).MatchString(header) {
+		t.Errorf("header got %q, want actual goroutine ID", header)
+	}
+}
+
+func TestFromPanic(t *testing.T) {
+	t.Parallel()
+
+	sentinel := errors.NewWithoutStack("sentinel")
+
+	tests := map[string]struct {
+		value   any
+		wantMsg string
+	}{
+		"string": {"boom", "panic: boom"},
+		"error":  {sentinel, "panic: sentinel"},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var err error
+			func() {
+				defer func() {
+					err = errors.FromPanic(recover())
+				}()
+				panic(tt.value)
+			}()
+
+			if err.Error() != tt.wantMsg {
+				t.Errorf("err.Error() got %q, want %q", err.Error(), tt.wantMsg)
+			}
+
+			if e, ok := tt.value.(error); ok && !errors.Is(err, e) {
+				t.Errorf("errors.Is(err, %v) should be true", e)
+			}
+
+			stack := string(stackOf(t, err))
+			if !regexp.MustCompile(`^goroutine [1-9][0-9]* \[running\]:\n`).MatchString(stack) {
+				t.Errorf("stack should start with actual goroutine header:\n%s", stack)
+			}
+			if !strings.Contains(stack, "panic(") || !strings.Contains(stack, "errors_test.TestFromPanic") {
+				t.Errorf("stack should contain panic and the panicking function:\n%s", stack)
+			}
+		})
+	}
+
+	if err := errors.FromPanic(nil); err != nil {
+		t.Errorf("errors.FromPanic(nil) got %v, want nil", err)
+	}
+}
+
+func TestParseStack(t *testing.T) {
+	t.Parallel()
+
+	stack := []byte(`goroutine 7 [running]:
+main.(*T).run(0xc000012345, {0x1, 0x2})
+	/app/main.go:12 +0x1d
+main.main()
+	/app/main.go:20 +0x25
+created by main.start in goroutine 1
+	/app/start.go:5 +0x3a
+`)
+
+	want := []errors.Frame{
+		{Function: "main.(*T).run", File: "/app/main.go", Line: 12},
+		{Function: "main.main", File: "/app/main.go", Line: 20},
+		{Function: "main.start", File: "/app/start.go", Line: 5},
+	}
+
+	if got := errors.ParseStack(stack); !reflect.DeepEqual(got, want) {
+		t.Errorf("errors.ParseStack() got %+v, want %+v", got, want)
+	}
+
+	frames := errors.ParseStack(stackOf(t, errors.New("foo")))
+	if len(frames) == 0 || frames[0].Function != "go.nownabe.dev/clog/errors_test.TestParseStack" || frames[0].Line == 0 {
+		t.Errorf("errors.ParseStack() got %+v", frames)
+	}
+}
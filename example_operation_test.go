@@ -20,6 +20,6 @@ func ExampleStartOperation() {
 	//  "logging.googleapis.com/operation":{"id":"long-running","producer":"my-app","first":true}, ...}
 	// {"severity":"INFO", "message":"long-running operation is running",
 	//  "logging.googleapis.com/operation":{"id":"long-running","producer":"my-app"}, ...}
-	// {"severity":"INFO", "message":"long-running operation ended",
+	// {"severity":"INFO", "message":"long-running operation ended", "duration":"0.000012345s",
 	//  "logging.googleapis.com/operation":{"id":"long-running","producer":"my-app","last":true}, ...}
 }
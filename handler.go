@@ -37,3 +37,20 @@ func (h *customHandler) WithGroup(group string) slog.Handler {
 	handler := h.Handler.WithGroup(group)
 	return &customHandler{handler, h.f, h.f(handler.Handle)}
 }
+
+func (h *customHandler) Flush(ctx context.Context) error {
+	return flush(ctx, h.Handler)
+}
+
+// flusher is implemented by handlers that buffer records.
+type flusher interface {
+	Flush(ctx context.Context) error
+}
+
+// flush flushes h if it buffers records.
+func flush(ctx context.Context, h slog.Handler) error {
+	if f, ok := h.(flusher); ok {
+		return f.Flush(ctx)
+	}
+	return nil
+}
@@ -67,8 +67,7 @@ func (r *HTTPRequest) LogValue() slog.Value {
 		attrs = append(attrs, slog.String("referer", r.Referer))
 	}
 	if r.Latency != 0 {
-		// https://protobuf.dev/reference/protobuf/google.protobuf/#duration
-		attrs = append(attrs, slog.String("latency", fmt.Sprintf("%.9fs", r.Latency.Seconds())))
+		attrs = append(attrs, slog.String("latency", durationString(r.Latency)))
 	}
 	if r.CacheLookup {
 		attrs = append(attrs, slog.Bool("cacheLookup", r.CacheLookup))
@@ -98,3 +97,9 @@ func (r *HTTPRequest) msg() string {
 	}
 	return msg
 }
+
+// durationString formats d in the JSON representation of google.protobuf.Duration like "1.500000000s".
+// See https://protobuf.dev/reference/protobuf/google.protobuf/#duration
+func durationString(d time.Duration) string {
+	return fmt.Sprintf("%.9fs", d.Seconds())
+}
@@ -18,3 +18,12 @@ const (
 	Trace          = apiPrefix + "trace"
 	TraceSampled   = apiPrefix + "trace_sampled"
 )
+
+// These keys are of attributes added by clog itself.
+const (
+	Causes   = "causes"
+	Duration = "duration"
+	Error    = "error"
+	Progress = "progress"
+	Repeated = "repeated"
+)
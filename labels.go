@@ -38,10 +38,16 @@ func (h *labelsHandler) Enabled(ctx context.Context, level slog.Level) bool {
 }
 
 func (h *labelsHandler) Handle(ctx context.Context, r slog.Record) error {
+	labels, hasLabels := ctx.Value(ctxKeyLabels{}).(*sync.Map)
+	defaultLabels, hasDefaultLabels := ctx.Value(ctxKeyDefaultLabels{}).(map[string]string)
+	if !hasLabels && len(defaultLabels) == 0 {
+		return h.Handler.Handle(ctx, r)
+	}
+
 	var attrs []slog.Attr
 	labelsKeys := map[string]struct{}{}
 
-	if labels, ok := ctx.Value(ctxKeyLabels{}).(*sync.Map); ok {
+	if hasLabels {
 		labels.Range(func(key, val any) bool {
 			keyStr, keyOK := key.(string)
 			valStr, valOK := val.(string)
@@ -53,7 +59,7 @@ func (h *labelsHandler) Handle(ctx context.Context, r slog.Record) error {
 		})
 	}
 
-	if defaultLabels, ok := ctx.Value(ctxKeyDefaultLabels{}).(map[string]string); ok {
+	if hasDefaultLabels {
 		for key, val := range defaultLabels {
 			if _, ok := labelsKeys[key]; !ok {
 				attrs = append(attrs, slog.String(key, val))
@@ -61,7 +67,13 @@ func (h *labelsHandler) Handle(ctx context.Context, r slog.Record) error {
 		}
 	}
 
-	r.AddAttrs(slog.Any(keys.Labels, slog.GroupValue(attrs...)))
+	if len(attrs) > 0 {
+		a := slog.Any(keys.Labels, slog.GroupValue(attrs...))
+		if rules, ok := ctx.Value(ctxKeyRedactionRules{}).([]RedactionRule); ok {
+			a = (&redactHandler{rules: rules}).redactAttr(a)
+		}
+		r.AddAttrs(a)
+	}
 
 	return h.Handler.Handle(ctx, r)
 }
@@ -74,21 +86,27 @@ func (h *labelsHandler) WithGroup(group string) slog.Handler {
 	return &labelsHandler{h.Handler.WithGroup(group)}
 }
 
+func (h *labelsHandler) Flush(ctx context.Context) error {
+	return flush(ctx, h.Handler)
+}
+
 // WithLabels returns an Option that sets the default labels.
+// When it is used more than once, the labels are merged, and the later ones win for the same keys.
 // See https://cloud.google.com/logging/docs/reference/v2/rest/v2/LogEntry
 func WithLabels(labels map[string]string) Option {
 	return optionFunc(func(h slog.Handler) slog.Handler {
-		return newDefaultLabelsHandler(h, labels)
+		return newDefaultLabelsHandler(h, func() map[string]string { return labels })
 	})
 }
 
 type defaultLabelsHandler struct {
 	slog.Handler
 
-	labels map[string]string
+	// labels is called on each Handle so that the labels can be resolved lazily.
+	labels func() map[string]string
 }
 
-func newDefaultLabelsHandler(h slog.Handler, labels map[string]string) slog.Handler {
+func newDefaultLabelsHandler(h slog.Handler, labels func() map[string]string) slog.Handler {
 	return &defaultLabelsHandler{h, labels}
 }
 
@@ -97,7 +115,21 @@ func (h *defaultLabelsHandler) Enabled(ctx context.Context, level slog.Level) bo
 }
 
 func (h *defaultLabelsHandler) Handle(ctx context.Context, r slog.Record) error {
-	ctx = context.WithValue(ctx, ctxKeyDefaultLabels{}, h.labels)
+	own := h.labels()
+	labels := own
+
+	// Labels set by outer handlers, which are set by later options, take precedence.
+	if outer, ok := ctx.Value(ctxKeyDefaultLabels{}).(map[string]string); ok && len(outer) > 0 {
+		labels = make(map[string]string, len(own)+len(outer))
+		for k, v := range own {
+			labels[k] = v
+		}
+		for k, v := range outer {
+			labels[k] = v
+		}
+	}
+
+	ctx = context.WithValue(ctx, ctxKeyDefaultLabels{}, labels)
 	return h.Handler.Handle(ctx, r)
 }
 
@@ -108,3 +140,7 @@ func (h *defaultLabelsHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
 func (h *defaultLabelsHandler) WithGroup(group string) slog.Handler {
 	return &defaultLabelsHandler{h.Handler.WithGroup(group), h.labels}
 }
+
+func (h *defaultLabelsHandler) Flush(ctx context.Context) error {
+	return flush(ctx, h.Handler)
+}
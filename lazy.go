@@ -0,0 +1,29 @@
+package clog
+
+import "log/slog"
+
+// Lazy returns a slog.LogValuer that calls f only when the log entry is actually written.
+// Use it for attribute values that are expensive to compute.
+//
+//	clog.Debug(ctx, "state", "dump", clog.Lazy(func() any { return dumpState() }))
+func Lazy(f func() any) slog.LogValuer {
+	return lazyValue(f)
+}
+
+type lazyValue func() any
+
+func (f lazyValue) LogValue() slog.Value {
+	return slog.AnyValue(f())
+}
+
+// LazyGroup returns a slog.LogValuer that calls f only when the log entry is actually written.
+// The returned Attrs are emitted as a group.
+func LazyGroup(f func() []slog.Attr) slog.LogValuer {
+	return lazyGroup(f)
+}
+
+type lazyGroup func() []slog.Attr
+
+func (f lazyGroup) LogValue() slog.Value {
+	return slog.GroupValue(f()...)
+}
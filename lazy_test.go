@@ -0,0 +1,64 @@
+package clog_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"go.nownabe.dev/clog"
+)
+
+func Test_Lazy(t *testing.T) {
+	t.Parallel()
+
+	l, w := newLogger(clog.SeverityInfo)
+
+	ctx := context.Background()
+	calls := 0
+
+	lazy := clog.Lazy(func() any {
+		calls++
+		return "v1"
+	})
+	lazyGroup := clog.LazyGroup(func() []slog.Attr {
+		calls++
+		return []slog.Attr{slog.String("k3", "v3")}
+	})
+
+	l.Debug(ctx, "msg", "k1", lazy, "k2", lazyGroup)
+	w.assertLog(t, nil)
+
+	if calls != 0 {
+		t.Errorf("lazy functions are called %d times for disabled log, want 0", calls)
+	}
+
+	l.Info(ctx, "msg", "k1", lazy, "k2", lazyGroup)
+	w.assertLog(t, buildWantLog("INFO", "msg", "k1", "v1", "k2", map[string]any{"k3": "v3"}))
+
+	if calls != 2 {
+		t.Errorf("lazy functions are called %d times, want 2", calls)
+	}
+}
+
+type formatCounter struct {
+	calls *int
+}
+
+func (f formatCounter) String() string {
+	*f.calls++
+	return "formatted"
+}
+
+func Test_FormattingLogFuncs_Disabled(t *testing.T) {
+	t.Parallel()
+
+	l, w := newLogger(clog.SeverityInfo)
+
+	calls := 0
+	l.Debugf(context.Background(), "%s", formatCounter{&calls})
+	w.assertLog(t, nil)
+
+	if calls != 0 {
+		t.Errorf("String() is called %d times for disabled log, want 0", calls)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"time"
 
 	"go.nownabe.dev/clog/errors"
 	"go.nownabe.dev/clog/internal/keys"
@@ -12,6 +13,7 @@ import (
 
 type Logger struct {
 	inner *slog.Logger
+	cfg   loggerConfig
 }
 
 func New(w io.Writer, s Severity, json bool, opts ...Option) *Logger {
@@ -32,14 +34,18 @@ func New(w io.Writer, s Severity, json bool, opts ...Option) *Logger {
 		h = slog.NewTextHandler(w, opt)
 	}
 
+	h = newRequestBufferHandler(h)
 	h = newLabelsHandler(h)
 	h = newOperationHandler(h)
 
-	for _, o := range opts {
-		h = o.apply(h)
+	h, cfg := applyOptions(h, loggerConfig{}, opts)
+
+	l := &Logger{slog.New(h), cfg}
+	if cfg.startupEntry {
+		l.logStartup(s, json)
 	}
 
-	return &Logger{slog.New(h)}
+	return l
 }
 
 // Debug logs at SeverityDebug.
@@ -49,7 +55,13 @@ func (l *Logger) Debug(ctx context.Context, msg string, args ...any) {
 
 // Debugf logs formatted in the manner of fmt.Printf at SeverityDebug.
 func (l *Logger) Debugf(ctx context.Context, format string, a ...any) {
-	l.log(ctx, SeverityDebug, fmt.Sprintf(format, a...))
+	l.logf(ctx, SeverityDebug, format, a...)
+}
+
+// DebugAttrs logs at SeverityDebug with the given Attrs.
+// It is more efficient than [Logger.Debug] because it doesn't box arguments.
+func (l *Logger) DebugAttrs(ctx context.Context, msg string, attrs ...slog.Attr) {
+	l.logAttrs(ctx, SeverityDebug, msg, attrs...)
 }
 
 // DebugErr logs an error at SeverityDebug.
@@ -64,7 +76,13 @@ func (l *Logger) Info(ctx context.Context, msg string, args ...any) {
 
 // Infof logs formatted in the manner of fmt.Printf at SeverityInfo.
 func (l *Logger) Infof(ctx context.Context, format string, a ...any) {
-	l.log(ctx, SeverityInfo, fmt.Sprintf(format, a...))
+	l.logf(ctx, SeverityInfo, format, a...)
+}
+
+// InfoAttrs logs at SeverityInfo with the given Attrs.
+// It is more efficient than [Logger.Info] because it doesn't box arguments.
+func (l *Logger) InfoAttrs(ctx context.Context, msg string, attrs ...slog.Attr) {
+	l.logAttrs(ctx, SeverityInfo, msg, attrs...)
 }
 
 // InfoErr logs an error at SeverityInfo.
@@ -79,7 +97,13 @@ func (l *Logger) Notice(ctx context.Context, msg string, args ...any) {
 
 // Noticef logs formatted in the manner of fmt.Printf at SeverityNotice.
 func (l *Logger) Noticef(ctx context.Context, format string, a ...any) {
-	l.log(ctx, SeverityNotice, fmt.Sprintf(format, a...))
+	l.logf(ctx, SeverityNotice, format, a...)
+}
+
+// NoticeAttrs logs at SeverityNotice with the given Attrs.
+// It is more efficient than [Logger.Notice] because it doesn't box arguments.
+func (l *Logger) NoticeAttrs(ctx context.Context, msg string, attrs ...slog.Attr) {
+	l.logAttrs(ctx, SeverityNotice, msg, attrs...)
 }
 
 // NoticeErr logs an error at SeverityNotice.
@@ -94,7 +118,13 @@ func (l *Logger) Warning(ctx context.Context, msg string, args ...any) {
 
 // Warningf logs formatted in the manner of fmt.Printf at SeverityWarning.
 func (l *Logger) Warningf(ctx context.Context, format string, a ...any) {
-	l.log(ctx, SeverityWarning, fmt.Sprintf(format, a...))
+	l.logf(ctx, SeverityWarning, format, a...)
+}
+
+// WarningAttrs logs at SeverityWarning with the given Attrs.
+// It is more efficient than [Logger.Warning] because it doesn't box arguments.
+func (l *Logger) WarningAttrs(ctx context.Context, msg string, attrs ...slog.Attr) {
+	l.logAttrs(ctx, SeverityWarning, msg, attrs...)
 }
 
 // WarningErr logs an error at SeverityWarning.
@@ -109,7 +139,13 @@ func (l *Logger) Error(ctx context.Context, msg string, args ...any) {
 
 // Errorf logs formatted in the manner of fmt.Printf at SeverityError.
 func (l *Logger) Errorf(ctx context.Context, format string, a ...any) {
-	l.log(ctx, SeverityError, fmt.Sprintf(format, a...))
+	l.logf(ctx, SeverityError, format, a...)
+}
+
+// ErrorAttrs logs at SeverityError with the given Attrs.
+// It is more efficient than [Logger.Error] because it doesn't box arguments.
+func (l *Logger) ErrorAttrs(ctx context.Context, msg string, attrs ...slog.Attr) {
+	l.logAttrs(ctx, SeverityError, msg, attrs...)
 }
 
 // ErrorErr logs an error at SeverityError.
@@ -124,7 +160,13 @@ func (l *Logger) Critical(ctx context.Context, msg string, args ...any) {
 
 // Criticalf logs formatted in the manner of fmt.Printf at SeverityCritical.
 func (l *Logger) Criticalf(ctx context.Context, format string, a ...any) {
-	l.log(ctx, SeverityCritical, fmt.Sprintf(format, a...))
+	l.logf(ctx, SeverityCritical, format, a...)
+}
+
+// CriticalAttrs logs at SeverityCritical with the given Attrs.
+// It is more efficient than [Logger.Critical] because it doesn't box arguments.
+func (l *Logger) CriticalAttrs(ctx context.Context, msg string, attrs ...slog.Attr) {
+	l.logAttrs(ctx, SeverityCritical, msg, attrs...)
 }
 
 // CriticalErr logs an error at SeverityCritical.
@@ -139,7 +181,13 @@ func (l *Logger) Alert(ctx context.Context, msg string, args ...any) {
 
 // Alertf logs formatted in the manner of fmt.Printf at SeverityAlert.
 func (l *Logger) Alertf(ctx context.Context, format string, a ...any) {
-	l.log(ctx, SeverityAlert, fmt.Sprintf(format, a...))
+	l.logf(ctx, SeverityAlert, format, a...)
+}
+
+// AlertAttrs logs at SeverityAlert with the given Attrs.
+// It is more efficient than [Logger.Alert] because it doesn't box arguments.
+func (l *Logger) AlertAttrs(ctx context.Context, msg string, attrs ...slog.Attr) {
+	l.logAttrs(ctx, SeverityAlert, msg, attrs...)
 }
 
 // AlertErr logs an error at SeverityAlert.
@@ -154,7 +202,13 @@ func (l *Logger) Emergency(ctx context.Context, msg string, args ...any) {
 
 // Emergencyf logs formatted in the manner of fmt.Printf at SeverityEmergency.
 func (l *Logger) Emergencyf(ctx context.Context, format string, a ...any) {
-	l.log(ctx, SeverityEmergency, fmt.Sprintf(format, a...))
+	l.logf(ctx, SeverityEmergency, format, a...)
+}
+
+// EmergencyAttrs logs at SeverityEmergency with the given Attrs.
+// It is more efficient than [Logger.Emergency] because it doesn't box arguments.
+func (l *Logger) EmergencyAttrs(ctx context.Context, msg string, attrs ...slog.Attr) {
+	l.logAttrs(ctx, SeverityEmergency, msg, attrs...)
 }
 
 // EmergencyErr logs an error at SeverityEmergency.
@@ -167,9 +221,25 @@ func (l *Logger) Enabled(ctx context.Context, s Severity) bool {
 	return l.inner.Enabled(ctx, s)
 }
 
-// Err is a shorthand for ErrorErr.
+// Err logs an error at the default severity of its code of the errors package,
+// e.g. SeverityWarning for NotFound, or at SeverityError if err has no code.
+// It is the same as ErrorErr for errors without codes.
 func (l *Logger) Err(ctx context.Context, err error, args ...any) {
-	l.err(ctx, SeverityError, err, args...)
+	l.err(ctx, errSeverity(err), err, args...)
+}
+
+// ErrAttrs logs an error with the given Attrs at the same severity as [Logger.Err],
+// which is SeverityError unless err has a code.
+// It is more efficient than [Logger.Err] because it doesn't box arguments.
+func (l *Logger) ErrAttrs(ctx context.Context, err error, attrs ...slog.Attr) {
+	s := errSeverity(err)
+	if err == nil || !l.Enabled(ctx, s) {
+		return
+	}
+
+	// skip [runtime.Callers, callerPC, l.sourceLocation, this function]
+	src := l.sourceLocation(4)
+	l.errAttrsWithSource(ctx, s, src, err, attrs...)
 }
 
 // Log emits a log record with the current time and the given level and message.
@@ -177,9 +247,20 @@ func (l *Logger) Log(ctx context.Context, s Severity, msg string, args ...any) {
 	l.log(ctx, s, msg, args...)
 }
 
+// LogAttrs is a more efficient version of [Logger.Log] that accepts only Attrs.
+func (l *Logger) LogAttrs(ctx context.Context, s Severity, msg string, attrs ...slog.Attr) {
+	l.logAttrs(ctx, s, msg, attrs...)
+}
+
+// LogErr logs an error at the highest severity attached by errors.WithSeverity in its wrap chain.
+// If no severity is attached, it is logged at the same severity as [Logger.Err].
+func (l *Logger) LogErr(ctx context.Context, err error, args ...any) {
+	l.err(ctx, suggestedSeverity(err), err, args...)
+}
+
 // With returns a Logger that includes the given attributes in each output operation.
 func (l *Logger) With(args ...any) *Logger {
-	return &Logger{l.inner.With(args...)}
+	return &Logger{l.inner.With(args...), l.cfg}
 }
 
 // HTTPReq emits a log with the given [HTTPRequest].
@@ -195,6 +276,16 @@ func (l *Logger) HTTPReq(ctx context.Context, req *HTTPRequest, args ...any) {
 	l.log(ctx, s, req.msg(), args...)
 }
 
+// WithCallerSkip returns a Logger that skips additional n stack frames
+// to determine logging.googleapis.com/sourceLocation.
+// It is useful for wrapper functions to attribute log entries to their callers.
+// See also [Helper].
+func (l *Logger) WithCallerSkip(n int) *Logger {
+	cfg := l.cfg
+	cfg.callerSkip += n
+	return &Logger{l.inner, cfg}
+}
+
 // WithInsertID returns a Logger that includes the given insertId in each output operation.
 // See https://cloud.google.com/logging/docs/reference/v2/rest/v2/LogEntry
 func (l *Logger) WithInsertID(id string) *Logger {
@@ -202,6 +293,10 @@ func (l *Logger) WithInsertID(id string) *Logger {
 }
 
 // StartOperation returns a new context and a function to end the opration, starting the operation.
+// The function is the same as [Operation.End] without args. Use [OperationFromContext] to end it with an error.
+// If id is empty, it is generated by the generator set by [WithOperationIDGenerator].
+// Operations started in another operation are nested; they record the parent ID as parent_operation_id
+// and inherit the producer if it is empty.
 // See https://cloud.google.com/logging/docs/reference/v2/rest/v2/LogEntry#LogEntryOperation
 func (l *Logger) StartOperation(
 	ctx context.Context, s Severity, msg, id, producer string,
@@ -209,66 +304,168 @@ func (l *Logger) StartOperation(
 	return l.startOperation(ctx, s, msg, id, producer)
 }
 
-func (l *Logger) log(ctx context.Context, s Severity, msg string, args ...any) {
-	// skip [runtime.Callers, source, this function, clog exported function]
-	src := getSourceLocation(4)
-	l.logWithSource(ctx, s, src, msg, args...)
+// LogAt emits a log record with the source location of the given program counter instead of the caller's.
+// It is useful for frameworks that log on behalf of user code.
+// pc is a return address like the ones returned by runtime.Callers. See also [FramePC].
+func (l *Logger) LogAt(ctx context.Context, pc uintptr, s Severity, msg string, args ...any) {
+	if !l.Enabled(ctx, s) {
+		return
+	}
+
+	l.logWithSource(ctx, s, l.sourceLocationAt(pc), msg, args...)
 }
 
-/*
-func (l *Logger) logAttrs(ctx context.Context, s Severity, msg string, attrs ...slog.Attr) {
-	// skip [runtime.Callers, source, this function, clog exported function]
-	src := getSourceLocation(4)
-	l.logAttrsWithSource(ctx, s, src, msg, attrs...)
+// LogAttrsAt is a more efficient version of [Logger.LogAt] that accepts only Attrs.
+func (l *Logger) LogAttrsAt(ctx context.Context, pc uintptr, s Severity, msg string, attrs ...slog.Attr) {
+	if !l.Enabled(ctx, s) {
+		return
+	}
+
+	l.logAttrsWithSource(ctx, s, l.sourceLocationAt(pc), msg, attrs...)
 }
-*/
 
-func (l *Logger) startOperation(
-	ctx context.Context, s Severity, msg, id, producer string,
-) (context.Context, func(msg string)) {
-	// skip [runtime.Callers, getSourceLocation, this function, exported function]
-	src := getSourceLocation(4)
+// ErrAt logs an error with the source location of the given program counter instead of the caller's.
+// See [Logger.LogAt].
+func (l *Logger) ErrAt(ctx context.Context, pc uintptr, s Severity, err error, args ...any) {
+	if err == nil || !l.Enabled(ctx, s) {
+		return
+	}
 
-	l.logAttrsWithSource(ctx, s, src, msg, slog.Group(keys.Operation, "id", id, "producer", producer, "first", true))
+	l.errWithSource(ctx, s, l.sourceLocationAt(pc), err, args...)
+}
 
-	opCtx := context.WithValue(ctx, ctxKeyOperation{}, &operation{id, producer})
+// Flush emits the log records buffered by the Logger's handlers such as [WithDedup].
+// Call it before the program exits.
+func (l *Logger) Flush(ctx context.Context) error {
+	return flush(ctx, l.inner.Handler())
+}
 
-	return opCtx, func(msg string) {
-		l.logAttrsWithSource(ctx, s, src, msg, slog.Group(keys.Operation, "id", id, "producer", producer, "last", true))
+func (l *Logger) log(ctx context.Context, s Severity, msg string, args ...any) {
+	if !l.Enabled(ctx, s) {
+		return
+	}
+
+	// skip [runtime.Callers, callerPC, l.sourceLocation, this function, clog exported function]
+	src := l.sourceLocation(5)
+	l.logWithSource(ctx, s, src, msg, args...)
+}
+
+// logf formats the message only if the Logger is enabled.
+func (l *Logger) logf(ctx context.Context, s Severity, format string, a ...any) {
+	if !l.Enabled(ctx, s) {
+		return
+	}
+
+	// skip [runtime.Callers, callerPC, l.sourceLocation, this function, clog exported function]
+	src := l.sourceLocation(5)
+	l.logWithSource(ctx, s, src, fmt.Sprintf(format, a...))
+}
+
+func (l *Logger) logAttrs(ctx context.Context, s Severity, msg string, attrs ...slog.Attr) {
+	if !l.Enabled(ctx, s) {
+		return
 	}
+
+	// skip [runtime.Callers, callerPC, l.sourceLocation, this function, clog exported function]
+	src := l.sourceLocation(5)
+	l.logAttrsWithSource(ctx, s, src, msg, attrs...)
 }
 
 func (l *Logger) withAttrs(attrs ...slog.Attr) *Logger {
-	return &Logger{slog.New(l.inner.Handler().WithAttrs(attrs))}
+	return &Logger{slog.New(l.inner.Handler().WithAttrs(attrs)), l.cfg}
 }
 
 func (l *Logger) err(ctx context.Context, s Severity, err error, args ...any) {
-	if err == nil {
+	if err == nil || !l.Enabled(ctx, s) {
 		return
 	}
 
-	attrs := argsToAttrs(args)
+	// skip [runtime.Callers, callerPC, l.sourceLocation, this function, clog exported function]
+	src := l.sourceLocation(5)
+	l.errWithSource(ctx, s, src, err, args...)
+}
+
+func (l *Logger) errWithSource(ctx context.Context, s Severity, src *sourceLocation, err error, args ...any) {
+	l.errAttrsWithSource(ctx, s, src, err, argsToAttrs(args)...)
+}
+
+func (l *Logger) errAttrsWithSource(
+	ctx context.Context, s Severity, src *sourceLocation, err error, attrs ...slog.Attr,
+) {
+	l.logAttrsWithSource(ctx, s, src, err.Error(), l.errorAttrs(attrs, err)...)
+}
+
+// errorAttrs appends the attributes describing err to attrs.
+func (l *Logger) errorAttrs(attrs []slog.Attr, err error) []slog.Attr {
+	attrs = appendErrorAttrs(attrs, errors.Attrs(err))
+	attrs = errorCodeAttrs(attrs, err)
+
+	// The first stack is emitted as stack_trace so that Error Reporting groups errors by it.
+	// The others are emitted with their messages as well not to lose them.
+	stacks := errors.Stacks(err)
+	if len(stacks) > 0 {
+		attrs = append(attrs, slog.String(keys.StackTrace, formatStack(stacks[0])))
+	}
+	if len(stacks) > 1 {
+		causes := make([]errorCause, len(stacks))
+		for i, ews := range stacks {
+			causes[i] = errorCause{ews.Error(), formatStack(ews)}
+		}
+		attrs = append(attrs, slog.Any(keys.Causes, causes))
+	}
+
+	if l.cfg.errorObject {
+		attrs = append(attrs, errorObjectAttr(err, stacks))
+	}
+
+	return attrs
+}
+
+// appendErrorAttrs appends the attributes attached to errors to the ones given at the call site.
+// The ones at the call site win for the same keys not to emit duplicate keys.
+func appendErrorAttrs(attrs, errAttrs []slog.Attr) []slog.Attr {
+	n := len(attrs)
 
-	var ews errors.ErrorWithStack
-	if errors.As(err, &ews) {
-		attrs = append(attrs, slog.String(keys.StackTrace, formatStack(ews)))
+outer:
+	for _, ea := range errAttrs {
+		for _, a := range attrs[:n] {
+			if a.Key == ea.Key {
+				continue outer
+			}
+		}
+		attrs = append(attrs, ea)
 	}
 
-	// skip [runtime.Callers, source, this function, clog exported function]
-	src := getSourceLocation(4)
-	l.logAttrsWithSource(ctx, s, src, err.Error(), attrs...)
+	return attrs
 }
 
 func (l *Logger) logWithSource(ctx context.Context, s Severity, src *sourceLocation, msg string, args ...any) {
-	args = append(args, keys.SourceLocation, src)
-	l.inner.Log(ctx, s, msg, args...)
+	r := slog.NewRecord(time.Now(), s, msg, 0)
+	r.Add(args...)
+	l.handle(ctx, src, r)
 }
 
 func (l *Logger) logAttrsWithSource(
 	ctx context.Context, s Severity, src *sourceLocation, msg string, attrs ...slog.Attr,
 ) {
-	attrs = append(attrs, slog.Any(keys.SourceLocation, src))
-	l.inner.LogAttrs(ctx, s, msg, attrs...)
+	r := slog.NewRecord(time.Now(), s, msg, 0)
+	r.AddAttrs(attrs...)
+	l.handle(ctx, src, r)
+}
+
+// handle passes r to the handler directly instead of slog.Logger's methods,
+// which avoids copying args and attrs to append the source location.
+// Callers must check l.Enabled in advance.
+func (l *Logger) handle(ctx context.Context, src *sourceLocation, r slog.Record) {
+	if src != nil {
+		r.AddAttrs(slog.Any(keys.SourceLocation, src))
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	_ = l.inner.Handler().Handle(ctx, r)
 }
 
 func argsToAttrs(args []any) []slog.Attr {
@@ -281,6 +478,7 @@ func argsToAttrs(args []any) []slog.Attr {
 		case string:
 			if len(args) == 1 {
 				attrs = append(attrs, slog.String(badKey, x))
+				args = args[1:]
 				break
 			}
 
@@ -298,6 +496,12 @@ func argsToAttrs(args []any) []slog.Attr {
 	return attrs
 }
 
+// errorCause is an error with stack in the tree of a logged error.
+type errorCause struct {
+	Message    string `json:"message"`
+	StackTrace string `json:"stack_trace"`
+}
+
 func formatStack(e errors.ErrorWithStack) string {
 	return e.Error() + "\n\n" + string(e.Stack())
 }
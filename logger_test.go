@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"regexp"
 	"testing"
 
@@ -82,6 +83,30 @@ func assertEqual(t *testing.T, want, got map[string]any) {
 			} else {
 				t.Errorf("got[%q] got %#v (%T), want map[string]any value: %#v", k, gotRawVal, gotRawVal, got)
 			}
+		case []map[string]any:
+			gotVals, ok := gotRawVal.([]any)
+			if !ok || len(gotVals) != len(wantVal) {
+				t.Errorf("got[%q] got %#v (%T), want %d elements: %#v", k, gotRawVal, gotRawVal, len(wantVal), got)
+				continue
+			}
+			for i, gotVal := range gotVals {
+				if gotMap, ok := gotVal.(map[string]any); ok {
+					assertEqual(t, wantVal[i], gotMap)
+				} else {
+					t.Errorf("got[%q][%d] got %#v (%T), want map[string]any value: %#v", k, i, gotVal, gotVal, got)
+				}
+			}
+		case []string:
+			gotVals, ok := gotRawVal.([]any)
+			if !ok || len(gotVals) != len(wantVal) {
+				t.Errorf("got[%q] got %#v (%T), want %q: %#v", k, gotRawVal, gotRawVal, wantVal, got)
+				continue
+			}
+			for i, gotVal := range gotVals {
+				if gotVal != wantVal[i] {
+					t.Errorf("got[%q][%d] got %#v, want %q: %#v", k, i, gotVal, wantVal[i], got)
+				}
+			}
 		case *regexp.Regexp:
 			if gotVal, ok := gotRawVal.(string); ok {
 				if !wantVal.MatchString(gotVal) {
@@ -646,3 +671,129 @@ func TestLogger_WithInsertID(t *testing.T) {
 	l.WithInsertID("id").Info(context.Background(), "msg")
 	w.assertLog(t, buildWantLog("INFO", "msg", "logging.googleapis.com/insertId", "id"))
 }
+
+func TestLogger_AttrsLogFuncs(t *testing.T) {
+	t.Parallel()
+
+	type logFn func(l *clog.Logger, ctx context.Context, msg string, attrs ...slog.Attr)
+
+	tests := map[string]struct {
+		fn             logFn
+		severityString string
+	}{
+		"Debug":     {(*clog.Logger).DebugAttrs, "DEBUG"},
+		"Info":      {(*clog.Logger).InfoAttrs, "INFO"},
+		"Notice":    {(*clog.Logger).NoticeAttrs, "NOTICE"},
+		"Warning":   {(*clog.Logger).WarningAttrs, "WARNING"},
+		"Error":     {(*clog.Logger).ErrorAttrs, "ERROR"},
+		"Critical":  {(*clog.Logger).CriticalAttrs, "CRITICAL"},
+		"Alert":     {(*clog.Logger).AlertAttrs, "ALERT"},
+		"Emergency": {(*clog.Logger).EmergencyAttrs, "EMERGENCY"},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			l, w := newLogger(clog.SeverityDebug)
+			tt.fn(l, context.Background(), "msg", slog.String("k1", "v1"), slog.Int("k2", 2))
+			w.assertLog(t, buildWantLog(tt.severityString, "msg", "k1", "v1", "k2", 2))
+		})
+	}
+}
+
+func TestLogger_LogAttrs(t *testing.T) {
+	t.Parallel()
+
+	l, w := newLogger(clog.SeverityInfo)
+	l.LogAttrs(context.Background(), clog.SeverityInfo, "msg", slog.String("k1", "v1"))
+	w.assertLog(t, buildWantLog("INFO", "msg", "k1", "v1"))
+}
+
+func TestLogger_ErrAttrs(t *testing.T) {
+	t.Parallel()
+
+	l, w := newLogger(clog.SeverityInfo)
+	l.ErrAttrs(context.Background(), errors.New("err"), slog.String("k1", "v1"))
+	w.assertLog(t, buildWantLog("ERROR", "err", "k1", "v1", "stack_trace", anyString{}))
+
+	l.ErrAttrs(context.Background(), nil)
+	w.assertLog(t, nil)
+}
+
+func TestLogger_Err_BadKey(t *testing.T) {
+	t.Parallel()
+
+	l, w := newLogger(clog.SeverityInfo)
+	l.Err(context.Background(), errors.NewWithoutStack("err"), "k1", "v1", "dangling")
+	w.assertLog(t, buildWantLog("ERROR", "err", "k1", "v1", "!BADKEY", "dangling"))
+}
+
+func TestLogger_Err_WithAttrs(t *testing.T) {
+	t.Parallel()
+
+	l, w := newLogger(clog.SeverityInfo)
+
+	err := errors.With(errors.NewWithoutStack("err"), "user_id", "u1")
+	err = errors.ErrorfWithoutStack("wrap: %w", err)
+	l.Err(context.Background(), err, "k1", "v1")
+	w.assertLog(t, buildWantLog("ERROR", "wrap: err", "k1", "v1", "user_id", "u1"))
+}
+
+func TestLogger_Err_WithAttrs_Duplicated(t *testing.T) {
+	t.Parallel()
+
+	l, w := newLogger(clog.SeverityInfo)
+
+	err := errors.With(errors.NewWithoutStack("err"), "user_id", "from error", "k1", "v1")
+	l.Err(context.Background(), err, "user_id", "from call site")
+	w.assertLog(t, buildWantLog("ERROR", "err", "user_id", "from call site", "k1", "v1"))
+}
+
+func TestLogger_Err_MultipleStacks(t *testing.T) {
+	t.Parallel()
+
+	err1 := errors.New("err1")
+	err2 := errors.New("err2")
+	err3 := errors.NewWithoutStack("err3")
+
+	tests := map[string]struct {
+		err  error
+		want map[string]any
+	}{
+		"joined": {
+			err: errors.Join(err1, err2, err3),
+			want: buildWantLog("ERROR", "err1\nerr2\nerr3",
+				"stack_trace", regexp.MustCompile(`^err1\n\ngoroutine `),
+				"causes", []map[string]any{
+					{"message": "err1", "stack_trace": regexp.MustCompile(`^err1\n\ngoroutine `)},
+					{"message": "err2", "stack_trace": regexp.MustCompile(`^err2\n\ngoroutine `)},
+				}),
+		},
+		"multiple %w": {
+			err: errors.Errorf("wrap: %w, %w", err1, err2),
+			want: buildWantLog("ERROR", "wrap: err1, err2",
+				"stack_trace", regexp.MustCompile(`^err1\n\ngoroutine `),
+				"causes", []map[string]any{
+					{"message": "err1", "stack_trace": anyString{}},
+					{"message": "err2", "stack_trace": anyString{}},
+				}),
+		},
+		"same stack": {
+			err:  errors.Join(err1, errors.Errorf("wrap: %w", err1)),
+			want: buildWantLog("ERROR", "err1\nwrap: err1", "stack_trace", regexp.MustCompile(`^err1\n\ngoroutine `)),
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			l, w := newLogger(clog.SeverityInfo)
+			l.Err(context.Background(), tt.err)
+			w.assertLog(t, tt.want)
+		})
+	}
+}
@@ -3,15 +3,153 @@ package clog
 import (
 	"context"
 	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"go.nownabe.dev/clog/internal/keys"
 )
 
 type ctxKeyOperation struct{}
 
-type operation struct {
+// Operation is an operation started by StartOperation.
+// It can be retrieved from the context by [OperationFromContext].
+type Operation struct {
 	id       string
 	producer string
+	parentID string
+
+	// l is nil for operations restored by the package-level ExtractOperation.
+	l         *Logger
+	extracted bool
+	severity  Severity
+	src       *sourceLocation
+	start     time.Time
+
+	// ctx is the context to log entries of the operation itself.
+	ctx context.Context //nolint:containedctx
+
+	ended     atomic.Bool
+	mu        sync.Mutex
+	progress  progress
+	heartbeat chan struct{}
+}
+
+// attrs returns the attributes of the operation with an additional flag like "first" or "last".
+func (op *Operation) attrs(args ...any) []slog.Attr {
+	attrs := []slog.Attr{slog.Group(keys.Operation, append([]any{"id", op.id, "producer", op.producer}, args...)...)}
+	if op.parentID != "" {
+		attrs = append(attrs, slog.String(parentOperationIDKey, op.parentID))
+	}
+
+	return attrs
+}
+
+const parentOperationIDKey = "parent_operation_id"
+
+// noOperation is set to contexts of the first and last entries of operations,
+// which have their own operation attributes, so that operationHandler doesn't add them again.
+var noOperation *Operation
+
+// OperationFromContext returns the operation in ctx started by StartOperation.
+// It returns nil if ctx has no operation. Methods of Operation are safe to call on nil.
+func OperationFromContext(ctx context.Context) *Operation {
+	op, _ := ctx.Value(ctxKeyOperation{}).(*Operation)
+	return op
+}
+
+// OperationID returns the ID of the operation in ctx started by StartOperation.
+// It returns an empty string if ctx has no operation.
+func OperationID(ctx context.Context) string {
+	if op := OperationFromContext(ctx); op != nil {
+		return op.id
+	}
+
+	return ""
+}
+
+// End ends the operation, logging msg and args at its severity with the last flag.
+// The entry has the elapsed time since the start as "duration".
+// Only the first call of End or EndErr logs, and the following calls do nothing.
+// It does nothing for operations restored by [ExtractOperation] as well.
+func (op *Operation) End(msg string, args ...any) {
+	op.end(nil, msg, argsToAttrs(args))
+}
+
+// EndErr ends the operation with err in the same way as End.
+// If err is not nil, the severity is escalated to the one of [Logger.LogErr] for err
+// if it is higher than the severity of the operation, and stack_trace and other attributes of err are attached.
+// Use [WithErrorObject] to log the message of err as well because msg is logged as the message of the entry.
+// It is convenient with named results:
+//
+//	func f(ctx context.Context) (err error) {
+//		ctx, _ = clog.StartOperation(ctx, clog.SeverityInfo, "started", "id", "producer")
+//		defer func() { clog.OperationFromContext(ctx).EndErr(err, "ended") }()
+//		...
+//	}
+func (op *Operation) EndErr(err error, msg string, args ...any) {
+	op.end(err, msg, argsToAttrs(args))
+}
+
+func (op *Operation) end(err error, msg string, attrs []slog.Attr) {
+	// Operations restored by ExtractOperation are ended by the process that started them.
+	if op == nil || op.extracted || op.ended.Swap(true) {
+		return
+	}
+
+	op.StopHeartbeat()
+
+	sev := op.severity
+	if err != nil {
+		sev = max(sev, suggestedSeverity(err))
+	}
+
+	if !op.l.Enabled(op.ctx, sev) {
+		return
+	}
+
+	attrs = append(attrs, slog.String(keys.Duration, durationString(time.Since(op.start))))
+	if err != nil {
+		attrs = op.l.errorAttrs(attrs, err)
+	}
+	attrs = append(attrs, op.attrs("last", true)...)
+
+	op.l.logAttrsWithSource(op.ctx, sev, op.src, msg, attrs...)
+}
+
+func (l *Logger) startOperation(
+	ctx context.Context, s Severity, msg, id, producer string,
+) (context.Context, func(msg string)) {
+	op := &Operation{id: id, producer: producer, l: l, severity: s}
+
+	// Operations started in another one are nested and record the parent.
+	if parent := OperationFromContext(ctx); parent != nil {
+		op.parentID = parent.id
+		if op.producer == "" {
+			op.producer = parent.producer
+		}
+	}
+
+	if op.id == "" {
+		gen := l.cfg.operationID
+		if gen == nil {
+			gen = UUIDOperationID
+		}
+		op.id = gen(ctx)
+	}
+
+	opCtx := context.WithValue(ctx, ctxKeyOperation{}, op)
+	ctx = context.WithValue(ctx, ctxKeyOperation{}, noOperation)
+
+	// The source location is needed even if the start isn't logged because errors may escalate the end.
+	// skip [runtime.Callers, callerPC, l.sourceLocation, this function, exported function]
+	op.ctx, op.src, op.start = ctx, l.sourceLocation(5), time.Now()
+
+	if l.Enabled(ctx, s) {
+		l.logAttrsWithSource(ctx, s, op.src, msg, op.attrs("first", true)...)
+	}
+
+	return opCtx, func(msg string) { op.end(nil, msg, nil) }
 }
 
 type operationHandler struct {
@@ -27,8 +165,8 @@ func (h *operationHandler) Enabled(ctx context.Context, level slog.Level) bool {
 }
 
 func (h *operationHandler) Handle(ctx context.Context, r slog.Record) error {
-	if op, ok := ctx.Value(ctxKeyOperation{}).(*operation); ok {
-		r.AddAttrs(slog.Group(keys.Operation, "id", op.id, "producer", op.producer))
+	if op := OperationFromContext(ctx); op != nil {
+		r.AddAttrs(op.attrs()...)
 	}
 
 	return h.Handler.Handle(ctx, r)
@@ -41,3 +179,7 @@ func (h *operationHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
 func (h *operationHandler) WithGroup(group string) slog.Handler {
 	return &operationHandler{h.Handler.WithGroup(group)}
 }
+
+func (h *operationHandler) Flush(ctx context.Context) error {
+	return flush(ctx, h.Handler)
+}
@@ -0,0 +1,81 @@
+package clog
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WithOperationIDGenerator returns an Option that sets the function to generate operation IDs
+// when StartOperation is called with an empty id.
+// The default is [UUIDOperationID].
+func WithOperationIDGenerator(g func(ctx context.Context) string) Option {
+	return loggerOption(func(c *loggerConfig) {
+		c.operationID = g
+	})
+}
+
+// UUIDOperationID generates a random UUID (version 4) as an operation ID.
+func UUIDOperationID(context.Context) string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // variant 10
+
+	var s [36]byte
+	hex.Encode(s[0:8], b[0:4])
+	s[8] = '-'
+	hex.Encode(s[9:13], b[4:6])
+	s[13] = '-'
+	hex.Encode(s[14:18], b[6:8])
+	s[18] = '-'
+	hex.Encode(s[19:23], b[8:10])
+	s[23] = '-'
+	hex.Encode(s[24:], b[10:])
+
+	return string(s[:])
+}
+
+// ULIDOperationID generates a ULID as an operation ID, which is lexicographically sortable by time.
+// See https://github.com/ulid/spec.
+func ULIDOperationID(context.Context) string {
+	const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+	// 48 bits of milliseconds and 80 bits of randomness.
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], uint64(time.Now().UnixMilli())<<16)
+	_, _ = rand.Read(b[6:])
+
+	hi := binary.BigEndian.Uint64(b[:8])
+	lo := binary.BigEndian.Uint64(b[8:])
+
+	// Encode 128 bits into 26 characters of 5 bits from the most significant bits.
+	var s [26]byte
+	for i := len(s) - 1; i >= 0; i-- {
+		s[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+
+	return string(s[:])
+}
+
+// TraceOperationID generates an operation ID derived from the trace ID in ctx like "<trace ID>-<random>",
+// so that operations can be associated with traces.
+// If ctx has no valid span context, it falls back to [UUIDOperationID].
+func TraceOperationID(ctx context.Context) string {
+	spanCtx := trace.SpanContextFromContext(ctx)
+	if !spanCtx.HasTraceID() {
+		return UUIDOperationID(ctx)
+	}
+
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+
+	return spanCtx.TraceID().String() + "-" + hex.EncodeToString(b[:])
+}
@@ -0,0 +1,143 @@
+package clog
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.nownabe.dev/clog/internal/keys"
+)
+
+const heartbeatMessage = "operation in progress"
+
+type progress struct {
+	done  int64
+	total int64
+	msg   string
+	at    time.Time
+}
+
+// Progress logs the progress of the operation at its severity with the same operation ID.
+// The entry has a "progress" group with done, total, percent complete, and ETA
+// estimated from the elapsed time since the start.
+// total can be zero if it's unknown, and then percent and ETA are omitted.
+func (op *Operation) Progress(ctx context.Context, done, total int64, msg string) {
+	if op == nil {
+		return
+	}
+
+	op.mu.Lock()
+	op.progress = progress{done, total, msg, time.Now()}
+	p := op.progress
+	op.mu.Unlock()
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = context.WithValue(ctx, ctxKeyOperation{}, noOperation)
+
+	l := op.logger()
+	if !l.Enabled(ctx, op.severity) {
+		return
+	}
+
+	// skip [runtime.Callers, callerPC, l.sourceLocation, this function]
+	src := l.sourceLocation(4)
+	l.logAttrsWithSource(ctx, op.severity, src, msg, op.progressAttrs(p)...)
+}
+
+// StartHeartbeat starts a goroutine that logs the latest progress of the operation every interval
+// until the operation ends or StopHeartbeat is called, so that stuck operations can be detected.
+// Calling it while a heartbeat is running does nothing.
+func (op *Operation) StartHeartbeat(interval time.Duration) {
+	if op == nil || interval <= 0 {
+		return
+	}
+
+	op.mu.Lock()
+	defer op.mu.Unlock()
+
+	if op.heartbeat != nil {
+		return
+	}
+
+	stop := make(chan struct{})
+	op.heartbeat = stop
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				op.beat(stop)
+			}
+		}
+	}()
+}
+
+// StopHeartbeat stops the heartbeat started by StartHeartbeat.
+func (op *Operation) StopHeartbeat() {
+	if op == nil {
+		return
+	}
+
+	op.mu.Lock()
+	defer op.mu.Unlock()
+
+	if op.heartbeat != nil {
+		close(op.heartbeat)
+		op.heartbeat = nil
+	}
+}
+
+func (op *Operation) beat(stop chan struct{}) {
+	l := op.logger()
+	if !l.Enabled(op.ctx, op.severity) {
+		return
+	}
+
+	// Log while holding the lock so that no heartbeat is logged after StopHeartbeat returns.
+	op.mu.Lock()
+	defer op.mu.Unlock()
+
+	if op.heartbeat != stop {
+		return
+	}
+	p := op.progress
+
+	msg := p.msg
+	if msg == "" {
+		msg = heartbeatMessage
+	}
+
+	l.logAttrsWithSource(op.ctx, op.severity, op.src, msg, op.progressAttrs(p)...)
+}
+
+// logger returns the Logger of the operation, or the default one for operations restored by ExtractOperation
+// without a Logger.
+func (op *Operation) logger() *Logger {
+	if op.l == nil {
+		return Default()
+	}
+
+	return op.l
+}
+
+func (op *Operation) progressAttrs(p progress) []slog.Attr {
+	args := []any{slog.Int64("done", p.done)}
+	if p.total > 0 {
+		args = append(args, slog.Int64("total", p.total), slog.Float64("percent", float64(p.done)*100/float64(p.total)))
+
+		if p.done > 0 && p.done <= p.total {
+			elapsed := p.at.Sub(op.start)
+			eta := time.Duration(float64(elapsed) * float64(p.total-p.done) / float64(p.done))
+			args = append(args, slog.String("eta", durationString(eta)))
+		}
+	}
+
+	return append([]slog.Attr{slog.Group(keys.Progress, args...)}, op.attrs()...)
+}
@@ -0,0 +1,89 @@
+package clog_test
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"go.nownabe.dev/clog"
+)
+
+func TestOperation_Progress(t *testing.T) {
+	t.Parallel()
+
+	l, w := newLogger(clog.SeverityInfo)
+	op := map[string]any{"id": "id", "producer": "producer"}
+
+	ctx, end := l.StartOperation(context.Background(), clog.SeverityNotice, "start", "id", "producer")
+	w.assertLog(t, buildWantLog("NOTICE", "start",
+		keyOperation, map[string]any{"id": "id", "producer": "producer", "first": true}))
+
+	o := clog.OperationFromContext(ctx)
+	if o == nil {
+		t.Fatal("clog.OperationFromContext(ctx) should not be nil")
+	}
+
+	o.Progress(ctx, 25, 100, "processing")
+	w.assertLog(t, buildWantLog("NOTICE", "processing",
+		"progress", map[string]any{"done": 25, "total": 100, "percent": 25, "eta": durationRE},
+		keyOperation, op))
+
+	o.Progress(ctx, 10, 0, "unknown total")
+	w.assertLog(t, buildWantLog("NOTICE", "unknown total", "progress", map[string]any{"done": 10}, keyOperation, op))
+
+	end("end")
+	w.assertLog(t, buildWantLog("NOTICE", "end", "duration", durationRE,
+		keyOperation, map[string]any{"id": "id", "producer": "producer", "last": true}))
+
+	// Methods are safe to call on nil.
+	var nilOp *clog.Operation
+	nilOp.Progress(ctx, 1, 2, "nil")
+	nilOp.StartHeartbeat(time.Millisecond)
+	nilOp.StopHeartbeat()
+	w.assertLog(t, nil)
+
+	if clog.OperationFromContext(context.Background()) != nil {
+		t.Error("clog.OperationFromContext(context.Background()) should be nil")
+	}
+}
+
+func TestOperation_Heartbeat(t *testing.T) {
+	t.Parallel()
+
+	sw := &syncWriter{}
+	l := clog.New(sw, clog.SeverityInfo, true)
+
+	ctx, end := l.StartOperation(context.Background(), clog.SeverityInfo, "start", "id", "producer")
+	o := clog.OperationFromContext(ctx)
+	o.StartHeartbeat(5 * time.Millisecond)
+	o.StartHeartbeat(5 * time.Millisecond)
+
+	w := sw.waitLines(t, 3)
+	w.assertLog(t, buildWantLog("INFO", "start",
+		keyOperation, map[string]any{"id": "id", "producer": "producer", "first": true}))
+	w.assertLog(t, buildWantLog("INFO", "operation in progress", "progress", map[string]any{"done": 0},
+		keyOperation, map[string]any{"id": "id", "producer": "producer"}))
+
+	end("end")
+
+	sw.mu.Lock()
+	b := bytes.Clone(sw.buf.Bytes())
+	sw.mu.Unlock()
+
+	lines := bytes.Split(bytes.TrimSpace(b), []byte{'\n'})
+	if last := lines[len(lines)-1]; !regexp.MustCompile(`"message":"end"`).Match(last) {
+		t.Errorf("the last entry got %s, want the end", last)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	sw.mu.Lock()
+	after := bytes.Clone(sw.buf.Bytes())
+	sw.mu.Unlock()
+
+	if len(after) != len(b) {
+		t.Errorf("heartbeat should stop after end: %s", after[len(b):])
+	}
+}
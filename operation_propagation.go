@@ -0,0 +1,116 @@
+package clog
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Keys of the operation propagated by InjectOperation.
+// HeaderCarrier canonicalizes them like "Clog-Operation-Id".
+const (
+	operationIDCarrierKey       = "clog-operation-id"
+	operationProducerCarrierKey = "clog-operation-producer"
+	operationParentCarrierKey   = "clog-operation-parent-id"
+	operationStartCarrierKey    = "clog-operation-start"
+	operationSeverityCarrierKey = "clog-operation-severity"
+)
+
+// OperationCarrier is a storage of the propagated operation such as HTTP headers and Pub/Sub message attributes.
+type OperationCarrier interface {
+	Get(key string) string
+	Set(key, value string)
+}
+
+// HeaderCarrier adapts http.Header to OperationCarrier.
+// It can be used for Cloud Tasks HTTP targets as well.
+type HeaderCarrier http.Header
+
+// Get returns the value of the header.
+func (c HeaderCarrier) Get(key string) string {
+	return http.Header(c).Get(key)
+}
+
+// Set sets the header.
+func (c HeaderCarrier) Set(key, value string) {
+	http.Header(c).Set(key, value)
+}
+
+// MapCarrier adapts map[string]string like Pub/Sub message attributes to OperationCarrier.
+type MapCarrier map[string]string
+
+// Get returns the value for the key.
+func (c MapCarrier) Get(key string) string {
+	return c[key]
+}
+
+// Set sets the value for the key.
+func (c MapCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+// InjectOperation writes the operation in ctx into c so that another process can continue it
+// by [ExtractOperation]. It does nothing if ctx has no operation.
+func InjectOperation(ctx context.Context, c OperationCarrier) {
+	op := OperationFromContext(ctx)
+	if op == nil {
+		return
+	}
+
+	c.Set(operationIDCarrierKey, op.id)
+	c.Set(operationProducerCarrierKey, op.producer)
+	if op.parentID != "" {
+		c.Set(operationParentCarrierKey, op.parentID)
+	}
+	if !op.start.IsZero() {
+		c.Set(operationStartCarrierKey, op.start.Format(time.RFC3339Nano))
+	}
+	c.Set(operationSeverityCarrierKey, strconv.Itoa(int(op.severity)))
+}
+
+// ExtractOperation returns a new context with the operation written into c by [InjectOperation].
+// Entries logged with the context share the operation, but neither first nor last entries are logged
+// because the process that started the operation is responsible for them.
+// Progress of the operation is logged by the default Logger at the severity of the operation,
+// and its ETA is estimated from the start time of the operation in the original process.
+// It returns ctx as it is if c has no operation.
+func ExtractOperation(ctx context.Context, c OperationCarrier) context.Context {
+	return extractOperation(ctx, c, nil)
+}
+
+// ExtractOperation is the same as the package-level [ExtractOperation],
+// except that progress of the operation is logged by l instead of the default Logger.
+func (l *Logger) ExtractOperation(ctx context.Context, c OperationCarrier) context.Context {
+	return extractOperation(ctx, c, l)
+}
+
+// extractOperation restores the operation in c. If l is nil, the default Logger is used.
+func extractOperation(ctx context.Context, c OperationCarrier, l *Logger) context.Context {
+	id := c.Get(operationIDCarrierKey)
+	if id == "" {
+		return ctx
+	}
+
+	op := &Operation{
+		id:        id,
+		producer:  c.Get(operationProducerCarrierKey),
+		parentID:  c.Get(operationParentCarrierKey),
+		l:         l,
+		extracted: true,
+		severity:  SeverityInfo,
+		start:     time.Now(),
+		ctx:       context.WithValue(ctx, ctxKeyOperation{}, noOperation),
+	}
+
+	// The defaults above are kept for carriers without them, such as the ones built by PubSubPushHandler
+	// and CloudTasksHandler from message IDs and task names.
+	if start, err := time.Parse(time.RFC3339Nano, c.Get(operationStartCarrierKey)); err == nil {
+		op.start = start
+	}
+	if s, err := strconv.Atoi(c.Get(operationSeverityCarrierKey)); err == nil {
+		op.severity = Severity(s)
+	}
+
+	return context.WithValue(ctx, ctxKeyOperation{}, op)
+}
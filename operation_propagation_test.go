@@ -0,0 +1,127 @@
+package clog_test
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"go.nownabe.dev/clog"
+)
+
+func TestInjectOperation(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		carrier clog.OperationCarrier
+	}{
+		"header": {clog.HeaderCarrier(http.Header{})},
+		"map":    {clog.MapCarrier{}},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			l, w := newLogger(clog.SeverityInfo)
+
+			// Producer process
+			ctx, end := l.StartOperation(context.Background(), clog.SeverityInfo, "start", "parent", "producer")
+			ctx, endChild := l.StartOperation(ctx, clog.SeverityInfo, "start child", "child", "")
+			clog.InjectOperation(ctx, tt.carrier)
+			endChild("end child")
+			end("end")
+
+			w.Reset()
+
+			// Consumer process
+			ctx = clog.ExtractOperation(context.Background(), tt.carrier)
+			if got := clog.OperationID(ctx); got != "child" {
+				t.Errorf("clog.OperationID(ctx) got %q, want %q", got, "child")
+			}
+
+			l.Info(ctx, "consumed")
+			w.assertLog(t, buildWantLog("INFO", "consumed", "parent_operation_id", "parent",
+				keyOperation, map[string]any{"id": "child", "producer": "producer"}))
+
+			// The consumer doesn't end the operation.
+			clog.OperationFromContext(ctx).End("end by consumer")
+			w.assertLog(t, nil)
+		})
+	}
+}
+
+func TestInjectOperation_NoOperation(t *testing.T) {
+	t.Parallel()
+
+	c := clog.MapCarrier{}
+	clog.InjectOperation(context.Background(), c)
+	if len(c) != 0 {
+		t.Errorf("carrier got %v, want empty", c)
+	}
+
+	ctx := context.Background()
+	if got := clog.ExtractOperation(ctx, c); got != ctx {
+		t.Errorf("clog.ExtractOperation() should return ctx as it is")
+	}
+}
+
+func TestExtractOperation_Progress(t *testing.T) {
+	w := setDefault(clog.SeverityInfo)
+
+	ctx := clog.ExtractOperation(context.Background(),
+		clog.MapCarrier{"clog-operation-id": "id", "clog-operation-producer": "producer"})
+	clog.OperationFromContext(ctx).Progress(ctx, 1, 2, "half")
+
+	w.assertLog(t, buildWantLog("INFO", "half",
+		"progress", map[string]any{"done": 1, "total": 2, "percent": 50, "eta": durationRE},
+		keyOperation, map[string]any{"id": "id", "producer": "producer"}))
+}
+
+func TestExtractOperation_StartAndSeverity(t *testing.T) {
+	w := setDefault(clog.SeverityInfo)
+
+	// Producer process
+	c := clog.MapCarrier{}
+	ctx, end := clog.StartOperation(context.Background(), clog.SeverityNotice, "start", "id", "producer")
+	clog.InjectOperation(ctx, c)
+	end("end")
+
+	for _, k := range []string{"clog-operation-start", "clog-operation-severity"} {
+		if c[k] == "" {
+			t.Errorf("carrier[%q] should be set: %v", k, c)
+		}
+	}
+
+	w.Reset()
+
+	// Consumer process started 10 seconds later
+	c["clog-operation-start"] = time.Now().Add(-10 * time.Second).Format(time.RFC3339Nano)
+	ctx = clog.ExtractOperation(context.Background(), c)
+	clog.OperationFromContext(ctx).Progress(ctx, 1, 2, "half")
+
+	w.assertLog(t, buildWantLog("NOTICE", "half",
+		"progress", map[string]any{"done": 1, "total": 2, "percent": 50, "eta": regexp.MustCompile(`^10\.\d{9}s$`)},
+		keyOperation, map[string]any{"id": "id", "producer": "producer"}))
+}
+
+func TestLogger_ExtractOperation(t *testing.T) {
+	t.Parallel()
+
+	l, w := newLogger(clog.SeverityInfo)
+
+	ctx := l.ExtractOperation(context.Background(),
+		clog.MapCarrier{"clog-operation-id": "id", "clog-operation-producer": "producer"})
+	op := clog.OperationFromContext(ctx)
+
+	op.Progress(ctx, 1, 2, "half")
+	w.assertLog(t, buildWantLog("INFO", "half",
+		"progress", map[string]any{"done": 1, "total": 2, "percent": 50, "eta": durationRE},
+		keyOperation, map[string]any{"id": "id", "producer": "producer"}))
+
+	// The consumer doesn't end the operation even with its own Logger.
+	op.End("end by consumer")
+	w.assertLog(t, nil)
+}
@@ -2,9 +2,15 @@ package clog_test
 
 import (
 	"context"
+	"log/slog"
+	"regexp"
 	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
 
 	"go.nownabe.dev/clog"
+	"go.nownabe.dev/clog/errors"
 )
 
 func Test_Operation(t *testing.T) {
@@ -29,9 +35,220 @@ func Test_Operation(t *testing.T) {
 			keyOperation, map[string]any{"id": "id", "producer": "producer"}))
 	}()
 
-	w.assertLog(t, buildWantLog("INFO", "end",
+	w.assertLog(t, buildWantLog("INFO", "end", "duration", durationRE,
 		keyOperation, map[string]any{"id": "id", "producer": "producer", "last": true}))
 
 	l.Info(ctx, "msg3")
 	w.assertLog(t, buildWantLog("INFO", "msg3"))
 }
+
+var durationRE = regexp.MustCompile(`^\d+\.\d{9}s$`)
+
+func TestOperation_End(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	lastOp := map[string]any{"id": "id", "producer": "producer", "last": true}
+
+	tests := map[string]struct {
+		severity clog.Severity
+		start    string
+		end      func(op *clog.Operation)
+		want     map[string]any
+	}{
+		"End": {
+			severity: clog.SeverityInfo,
+			start:    "INFO",
+			end:      func(op *clog.Operation) { op.End("end", "k1", "v1", slog.String("k2", "v2")) },
+			want:     buildWantLog("INFO", "end", "k1", "v1", "k2", "v2", "duration", durationRE, keyOperation, lastOp),
+		},
+		"EndErr nil": {
+			severity: clog.SeverityInfo,
+			start:    "INFO",
+			end:      func(op *clog.Operation) { op.EndErr(nil, "end") },
+			want:     buildWantLog("INFO", "end", "duration", durationRE, keyOperation, lastOp),
+		},
+		"EndErr": {
+			severity: clog.SeverityInfo,
+			start:    "INFO",
+			end:      func(op *clog.Operation) { op.EndErr(errors.New("err"), "end", "k1", "v1") },
+			want: buildWantLog("ERROR", "end", "k1", "v1", "duration", durationRE,
+				"stack_trace", regexp.MustCompile(`^err\n\ngoroutine `), keyOperation, lastOp),
+		},
+		"EndErr code": {
+			severity: clog.SeverityInfo,
+			start:    "INFO",
+			end: func(op *clog.Operation) {
+				op.EndErr(errors.WithCode(errors.NewWithoutStack("not found"), errors.NotFound), "end")
+			},
+			want: buildWantLog("WARNING", "end", "duration", durationRE,
+				"error_code", "NotFound", "retriable", false, keyOperation, lastOp),
+		},
+		"EndErr lower severity": {
+			severity: clog.SeverityCritical,
+			start:    "CRITICAL",
+			end:      func(op *clog.Operation) { op.EndErr(errors.NewWithoutStack("err"), "end") },
+			want:     buildWantLog("CRITICAL", "end", "duration", durationRE, keyOperation, lastOp),
+		},
+		"twice": {
+			severity: clog.SeverityInfo,
+			start:    "INFO",
+			end: func(op *clog.Operation) {
+				op.End("end")
+				op.EndErr(errors.New("err"), "end again")
+			},
+			want: buildWantLog("INFO", "end", "duration", durationRE, keyOperation, lastOp),
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			l, w := newLogger(clog.SeverityInfo)
+
+			ctx, end := l.StartOperation(ctx, tt.severity, "start", "id", "producer")
+			w.assertLog(t, buildWantLog(tt.start, "start",
+				keyOperation, map[string]any{"id": "id", "producer": "producer", "first": true}))
+
+			tt.end(clog.OperationFromContext(ctx))
+			w.assertLog(t, tt.want)
+
+			end("end by func")
+			w.assertLog(t, nil)
+		})
+	}
+}
+
+func TestOperation_End_Escalated(t *testing.T) {
+	t.Parallel()
+
+	l, w := newLogger(clog.SeverityWarning)
+
+	_, end := l.StartOperation(context.Background(), clog.SeverityInfo, "start", "id", "producer")
+	w.assertLog(t, nil)
+
+	end("end")
+	w.assertLog(t, nil)
+
+	ctx, _ := l.StartOperation(context.Background(), clog.SeverityInfo, "start", "id", "producer")
+	clog.OperationFromContext(ctx).EndErr(errors.NewWithoutStack("err"), "end")
+	w.assertLog(t, buildWantLog("ERROR", "end", "duration", durationRE,
+		keyOperation, map[string]any{"id": "id", "producer": "producer", "last": true}))
+}
+
+func TestOperation_End_ErrorObject(t *testing.T) {
+	t.Parallel()
+
+	l, w := newLogger(clog.SeverityInfo, clog.WithErrorObject())
+
+	ctx, _ := l.StartOperation(context.Background(), clog.SeverityInfo, "start", "id", "producer")
+	w.Reset()
+
+	clog.OperationFromContext(ctx).EndErr(errors.NewWithoutStack("err"), "end")
+	w.assertLog(t, buildWantLog("ERROR", "end", "duration", durationRE,
+		"error", map[string]any{"type": "*errors.errorString", "message": "err"},
+		keyOperation, map[string]any{"id": "id", "producer": "producer", "last": true}))
+}
+
+func TestStartOperation_Nested(t *testing.T) {
+	t.Parallel()
+
+	l, w := newLogger(clog.SeverityInfo)
+
+	ctx, endParent := l.StartOperation(context.Background(), clog.SeverityInfo, "start", "parent", "producer")
+	w.assertLog(t, buildWantLog("INFO", "start",
+		keyOperation, map[string]any{"id": "parent", "producer": "producer", "first": true}))
+
+	childCtx, endChild := l.StartOperation(ctx, clog.SeverityInfo, "start child", "child", "")
+	w.assertLog(t, buildWantLog("INFO", "start child", "parent_operation_id", "parent",
+		keyOperation, map[string]any{"id": "child", "producer": "producer", "first": true}))
+
+	if got := clog.OperationID(childCtx); got != "child" {
+		t.Errorf("clog.OperationID(childCtx) got %q, want %q", got, "child")
+	}
+
+	l.Info(childCtx, "in child")
+	w.assertLog(t, buildWantLog("INFO", "in child", "parent_operation_id", "parent",
+		keyOperation, map[string]any{"id": "child", "producer": "producer"}))
+
+	endChild("end child")
+	w.assertLog(t, buildWantLog("INFO", "end child", "parent_operation_id", "parent", "duration", durationRE,
+		keyOperation, map[string]any{"id": "child", "producer": "producer", "last": true}))
+
+	l.Info(ctx, "in parent")
+	w.assertLog(t, buildWantLog("INFO", "in parent",
+		keyOperation, map[string]any{"id": "parent", "producer": "producer"}))
+
+	endParent("end")
+	w.assertLog(t, buildWantLog("INFO", "end", "duration", durationRE,
+		keyOperation, map[string]any{"id": "parent", "producer": "producer", "last": true}))
+
+	if got := clog.OperationID(context.Background()); got != "" {
+		t.Errorf("clog.OperationID(context.Background()) got %q, want empty", got)
+	}
+}
+
+func TestStartOperation_GeneratedID(t *testing.T) {
+	t.Parallel()
+
+	uuidRE := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	ulidRE := regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`)
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	traceCtx := trace.ContextWithSpanContext(context.Background(),
+		trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID}))
+
+	tests := map[string]struct {
+		ctx  context.Context
+		opts []clog.Option
+		want *regexp.Regexp
+	}{
+		"default": {context.Background(), nil, uuidRE},
+		"UUID":    {context.Background(), []clog.Option{clog.WithOperationIDGenerator(clog.UUIDOperationID)}, uuidRE},
+		"ULID":    {context.Background(), []clog.Option{clog.WithOperationIDGenerator(clog.ULIDOperationID)}, ulidRE},
+		"trace": {
+			traceCtx,
+			[]clog.Option{clog.WithOperationIDGenerator(clog.TraceOperationID)},
+			regexp.MustCompile(`^4bf92f3577b34da6a3ce929d0e0e4736-[0-9a-f]{16}$`),
+		},
+		"trace missing": {context.Background(), []clog.Option{clog.WithOperationIDGenerator(clog.TraceOperationID)}, uuidRE},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			l, w := newLogger(clog.SeverityInfo, tt.opts...)
+
+			ctx, _ := l.StartOperation(tt.ctx, clog.SeverityInfo, "start", "", "producer")
+			id := clog.OperationID(ctx)
+			if !tt.want.MatchString(id) {
+				t.Errorf("clog.OperationID(ctx) got %q, want match %v", id, tt.want)
+			}
+
+			ctx2, _ := l.StartOperation(tt.ctx, clog.SeverityInfo, "start", "", "producer")
+			if id2 := clog.OperationID(ctx2); id2 == id {
+				t.Errorf("generated IDs should be unique: %q", id)
+			}
+
+			w.assertLog(t, buildWantLog("INFO", "start",
+				keyOperation, map[string]any{"id": id, "producer": "producer", "first": true}))
+		})
+	}
+}
+
+func TestULIDOperationID_Sortable(t *testing.T) {
+	t.Parallel()
+
+	id1 := clog.ULIDOperationID(context.Background())
+	time.Sleep(2 * time.Millisecond)
+	id2 := clog.ULIDOperationID(context.Background())
+
+	if id1[:10] >= id2[:10] {
+		t.Errorf("ULIDs should be sortable by time: %q, %q", id1, id2)
+	}
+}
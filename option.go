@@ -1,6 +1,9 @@
 package clog
 
-import "log/slog"
+import (
+	"context"
+	"log/slog"
+)
 
 type Option interface {
 	apply(h slog.Handler) slog.Handler
@@ -11,3 +14,31 @@ type optionFunc func(h slog.Handler) slog.Handler
 func (f optionFunc) apply(h slog.Handler) slog.Handler {
 	return f(h)
 }
+
+// loggerConfig is the configuration of Logger itself, not of its handler.
+type loggerConfig struct {
+	source      sourceLocationConfig
+	callerSkip  int
+	errorObject bool
+	operationID func(ctx context.Context) string
+
+	startupEntry bool
+}
+
+// loggerOption is an Option that configures the Logger instead of its handler.
+type loggerOption func(c *loggerConfig)
+
+func (f loggerOption) apply(h slog.Handler) slog.Handler {
+	return h
+}
+
+func applyOptions(h slog.Handler, c loggerConfig, opts []Option) (slog.Handler, loggerConfig) {
+	for _, o := range opts {
+		h = o.apply(h)
+		if lo, ok := o.(loggerOption); ok {
+			lo(&c)
+		}
+	}
+
+	return h, c
+}
@@ -0,0 +1,114 @@
+package clog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"go.nownabe.dev/clog/errors"
+)
+
+// Labels set by PubSubPushHandler.
+const (
+	pubsubMessageIDLabel       = "pubsub_message_id"
+	pubsubSubscriptionLabel    = "pubsub_subscription"
+	pubsubPublishTimeLabel     = "pubsub_publish_time"
+	pubsubDeliveryAttemptLabel = "pubsub_delivery_attempt"
+)
+
+// pubsubPushMaxBodySize is the limit of push request bodies,
+// which is large enough for the maximum message size of 10 MB encoded in base64.
+const pubsubPushMaxBodySize = 16 << 20
+
+// pubsubPushEnvelope is the request body of Pub/Sub push subscriptions.
+// See https://cloud.google.com/pubsub/docs/push#receive_push.
+type pubsubPushEnvelope struct {
+	Message struct {
+		Attributes  map[string]string `json:"attributes"`
+		MessageID   string            `json:"messageId"`
+		PublishTime string            `json:"publishTime"`
+	} `json:"message"`
+	Subscription    string `json:"subscription"`
+	DeliveryAttempt int    `json:"deliveryAttempt"`
+}
+
+// PubSubPushHandler returns a middleware for Pub/Sub push subscriptions.
+// It parses the push envelope in the request body and sets the message ID, the subscription,
+// the publish time, and the delivery attempt to labels of the request context.
+// Entries logged with the context share an operation, which is the one propagated by [InjectOperation]
+// in the message attributes if any, or otherwise the one identified by the message ID and the subscription.
+// Neither first nor last entries of the operation are logged.
+// The body is still readable by h. Requests whose body isn't an envelope are passed through as they are,
+// while requests whose body is larger than 16 MB are rejected with 413 Request Entity Too Large.
+func PubSubPushHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, pubsubPushMaxBodySize))
+		_ = r.Body.Close()
+
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		var env pubsubPushEnvelope
+		if err != nil || json.Unmarshal(body, &env) != nil || env.Message.MessageID == "" {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := r.Context()
+
+		labels := map[string]string{
+			pubsubMessageIDLabel:    env.Message.MessageID,
+			pubsubSubscriptionLabel: env.Subscription,
+			pubsubPublishTimeLabel:  env.Message.PublishTime,
+		}
+		if env.DeliveryAttempt > 0 {
+			labels[pubsubDeliveryAttemptLabel] = strconv.Itoa(env.DeliveryAttempt)
+		}
+
+		ctx = contextWithLabels(ctx, labels)
+		ctx = extractOperationOr(ctx, MapCarrier(env.Message.Attributes), env.Message.MessageID, env.Subscription)
+
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// contextWithLabels returns a new context with non-empty labels in addition to the ones in ctx.
+// Unlike ContextWithLabel, the labels are set to a copy of the labels in ctx,
+// so they don't leak to other requests sharing the parent context such as the one of BaseContext.
+func contextWithLabels(ctx context.Context, labels map[string]string) context.Context {
+	copied := &sync.Map{}
+
+	if parent, ok := ctx.Value(ctxKeyLabels{}).(*sync.Map); ok {
+		parent.Range(func(k, v any) bool {
+			copied.Store(k, v)
+			return true
+		})
+	}
+
+	for k, v := range labels {
+		if v != "" {
+			copied.Store(k, v)
+		}
+	}
+
+	return context.WithValue(ctx, ctxKeyLabels{}, copied)
+}
+
+// extractOperationOr restores the operation propagated in c,
+// or otherwise sets the operation identified by id and producer to ctx.
+func extractOperationOr(ctx context.Context, c OperationCarrier, id, producer string) context.Context {
+	if c.Get(operationIDCarrierKey) == "" {
+		c = MapCarrier{operationIDCarrierKey: id, operationProducerCarrierKey: producer}
+	}
+
+	return ExtractOperation(ctx, c)
+}
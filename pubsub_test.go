@@ -0,0 +1,135 @@
+package clog_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.nownabe.dev/clog"
+)
+
+func TestPubSubPushHandler(t *testing.T) {
+	t.Parallel()
+
+	const envelope = `{
+		"message": {
+			"attributes": {"k": "v"},
+			"data": "aGVsbG8=",
+			"messageId": "123",
+			"publishTime": "2024-01-01T00:00:00Z"
+		},
+		"subscription": "projects/p/subscriptions/s",
+		"deliveryAttempt": 2
+	}`
+
+	const propagated = `{
+		"message": {
+			"attributes": {"clog-operation-id": "op", "clog-operation-producer": "producer"},
+			"messageId": "123"
+		},
+		"subscription": "projects/p/subscriptions/s"
+	}`
+
+	tests := map[string]struct {
+		body string
+		want map[string]any
+	}{
+		"envelope": {
+			body: envelope,
+			want: buildWantLog("INFO", "msg",
+				keyLabels, map[string]any{
+					"pubsub_message_id":       "123",
+					"pubsub_subscription":     "projects/p/subscriptions/s",
+					"pubsub_publish_time":     "2024-01-01T00:00:00Z",
+					"pubsub_delivery_attempt": "2",
+				},
+				keyOperation, map[string]any{"id": "123", "producer": "projects/p/subscriptions/s"}),
+		},
+		"propagated operation": {
+			body: propagated,
+			want: buildWantLog("INFO", "msg",
+				keyLabels, map[string]any{
+					"pubsub_message_id":   "123",
+					"pubsub_subscription": "projects/p/subscriptions/s",
+				},
+				keyOperation, map[string]any{"id": "op", "producer": "producer"}),
+		},
+		"not envelope": {
+			body: `{"foo": "bar"}`,
+			want: buildWantLog("INFO", "msg"),
+		},
+		"not JSON": {
+			body: `foo`,
+			want: buildWantLog("INFO", "msg"),
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			l, w := newLogger(clog.SeverityInfo)
+
+			h := clog.PubSubPushHandler(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				l.Info(r.Context(), "msg")
+
+				body, err := io.ReadAll(r.Body)
+				if err != nil || string(body) != tt.body {
+					t.Errorf("body got %q (%v), want %q", body, err, tt.body)
+				}
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			w.assertLog(t, tt.want)
+
+			// Labels are removed after the request.
+			l.Info(context.Background(), "after")
+			w.assertLog(t, buildWantLog("INFO", "after"))
+		})
+	}
+}
+
+func TestPubSubPushHandler_ParentLabels(t *testing.T) {
+	t.Parallel()
+
+	l, w := newLogger(clog.SeverityInfo)
+
+	// Like BaseContext of http.Server, the parent context is shared by requests.
+	parent, _ := clog.ContextWithLabel(context.Background(), "base", "v")
+
+	h := clog.PubSubPushHandler(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		l.Info(r.Context(), "msg")
+		l.Info(parent, "parent")
+	}))
+
+	body := `{"message": {"messageId": "123"}, "subscription": "s"}`
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)).WithContext(parent)
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	w.assertLog(t, buildWantLog("INFO", "msg",
+		keyLabels, map[string]any{"base": "v", "pubsub_message_id": "123", "pubsub_subscription": "s"},
+		keyOperation, map[string]any{"id": "123", "producer": "s"}))
+	w.assertLog(t, buildWantLog("INFO", "parent", keyLabels, map[string]any{"base": "v"}))
+}
+
+func TestPubSubPushHandler_TooLarge(t *testing.T) {
+	t.Parallel()
+
+	h := clog.PubSubPushHandler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		t.Error("handler should not be called")
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(strings.Repeat("a", 16<<20+1)))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status code got %d, want %d", rec.Code, http.StatusRequestEntityTooLarge)
+	}
+}
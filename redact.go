@@ -0,0 +1,273 @@
+package clog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"path"
+	"reflect"
+	"regexp"
+
+	"go.nownabe.dev/clog/internal/keys"
+)
+
+const redacted = "[REDACTED]"
+
+// Secret is a string that is always masked in log entries.
+type Secret string
+
+// LogValue returns the masked value.
+func (Secret) LogValue() slog.Value {
+	return slog.StringValue(redacted)
+}
+
+// String returns the masked value.
+func (Secret) String() string {
+	return redacted
+}
+
+// RedactionRule is a rule to mask attributes. Use [WithRedaction] to apply rules.
+type RedactionRule struct {
+	key   func(key string) bool
+	value func(s string) string
+}
+
+// RedactKeys returns a RedactionRule that masks values of attributes whose keys match any of the glob patterns.
+// The pattern syntax is the same as [path.Match]. Malformed patterns never match.
+func RedactKeys(patterns ...string) RedactionRule {
+	return RedactionRule{key: func(key string) bool {
+		for _, p := range patterns {
+			if ok, _ := path.Match(p, key); ok {
+				return true
+			}
+		}
+		return false
+	}}
+}
+
+// RedactKeysRegexp returns a RedactionRule that masks values of attributes whose keys match re.
+func RedactKeysRegexp(re *regexp.Regexp) RedactionRule {
+	return RedactionRule{key: re.MatchString}
+}
+
+// RedactValues returns a RedactionRule that masks substrings matching re
+// in messages and string values of attributes.
+func RedactValues(re *regexp.Regexp) RedactionRule {
+	return RedactionRule{value: func(s string) string {
+		return re.ReplaceAllLiteralString(s, redacted)
+	}}
+}
+
+var (
+	emailRE       = regexp.MustCompile(`[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}`)
+	bearerTokenRE = regexp.MustCompile(`(?i)\bbearer\s+[a-zA-Z0-9\-._~+/]+=*`)
+	cardNumberRE  = regexp.MustCompile(`\b\d(?:[ \-]?\d){12,18}\b`)
+)
+
+// RedactEmails returns a RedactionRule that masks email addresses.
+func RedactEmails() RedactionRule {
+	return RedactValues(emailRE)
+}
+
+// RedactBearerTokens returns a RedactionRule that masks bearer tokens like "Bearer xxxxx".
+func RedactBearerTokens() RedactionRule {
+	return RedactValues(bearerTokenRE)
+}
+
+// RedactCreditCards returns a RedactionRule that masks credit card numbers passing the Luhn check.
+func RedactCreditCards() RedactionRule {
+	return RedactionRule{value: func(s string) string {
+		return cardNumberRE.ReplaceAllStringFunc(s, func(m string) string {
+			if luhn(m) {
+				return redacted
+			}
+			return m
+		})
+	}}
+}
+
+// luhn reports whether the digits in s pass the Luhn check.
+// See https://en.wikipedia.org/wiki/Luhn_algorithm.
+func luhn(s string) bool {
+	sum := 0
+	double := false
+
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i] < '0' || '9' < s[i] {
+			continue
+		}
+
+		d := int(s[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+
+	return sum%10 == 0
+}
+
+// WithRedaction returns an Option that masks attributes and messages with the given rules.
+// Rules are applied to attributes inside groups, values nested in maps, slices, and structs
+// like error objects, and labels as well.
+func WithRedaction(rules ...RedactionRule) Option {
+	return optionFunc(func(h slog.Handler) slog.Handler {
+		return &redactHandler{h, rules}
+	})
+}
+
+type redactHandler struct {
+	slog.Handler
+
+	rules []RedactionRule
+}
+
+func (h *redactHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.Handler.Enabled(ctx, level)
+}
+
+// ctxKeyRedactionRules holds the rules of all redactHandlers in the chain
+// so that labelsHandler, which is inner, masks labels as well.
+type ctxKeyRedactionRules struct{}
+
+func (h *redactHandler) Handle(ctx context.Context, r slog.Record) error {
+	rules, _ := ctx.Value(ctxKeyRedactionRules{}).([]RedactionRule)
+	ctx = context.WithValue(ctx, ctxKeyRedactionRules{}, append(rules[:len(rules):len(rules)], h.rules...))
+
+	nr := slog.NewRecord(r.Time, r.Level, h.redactString(r.Message), r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		nr.AddAttrs(h.redactAttr(a))
+		return true
+	})
+
+	return h.Handler.Handle(ctx, nr)
+}
+
+func (h *redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redactedAttrs := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redactedAttrs[i] = h.redactAttr(a)
+	}
+
+	return &redactHandler{h.Handler.WithAttrs(redactedAttrs), h.rules}
+}
+
+func (h *redactHandler) WithGroup(group string) slog.Handler {
+	return &redactHandler{h.Handler.WithGroup(group), h.rules}
+}
+
+func (h *redactHandler) Flush(ctx context.Context) error {
+	return flush(ctx, h.Handler)
+}
+
+func (h *redactHandler) redactAttr(a slog.Attr) slog.Attr {
+	// sourceLocation is kept as is so that inner handlers can inspect it.
+	if a.Key == keys.SourceLocation {
+		return a
+	}
+
+	if h.matchKey(a.Key) {
+		return slog.String(a.Key, redacted)
+	}
+
+	v := a.Value.Resolve()
+
+	switch v.Kind() {
+	case slog.KindGroup:
+		group := v.Group()
+		attrs := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			attrs[i] = h.redactAttr(ga)
+		}
+		a.Value = slog.GroupValue(attrs...)
+	case slog.KindString:
+		a.Value = slog.StringValue(h.redactString(v.String()))
+	case slog.KindAny:
+		a.Value = h.redactAny(v)
+	default:
+		a.Value = v
+	}
+
+	return a
+}
+
+// redactAny masks errors and values nested in maps, slices, and structs such as error objects.
+// Nested values are masked in their JSON representations, which are what JSON handlers emit.
+func (h *redactHandler) redactAny(v slog.Value) slog.Value {
+	if err, ok := v.Any().(error); ok {
+		return slog.StringValue(h.redactString(err.Error()))
+	}
+
+	rv := reflect.ValueOf(v.Any())
+	for rv.Kind() == reflect.Pointer {
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() { //nolint:exhaustive
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct:
+	default:
+		return v
+	}
+
+	b, err := json.Marshal(v.Any())
+	if err != nil {
+		return v
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var x any
+	if err := dec.Decode(&x); err != nil {
+		return v
+	}
+
+	return slog.AnyValue(h.redactJSON(x))
+}
+
+// redactJSON masks x decoded from JSON in the same way as attributes.
+func (h *redactHandler) redactJSON(x any) any {
+	switch x := x.(type) {
+	case map[string]any:
+		for k, v := range x {
+			if h.matchKey(k) {
+				x[k] = redacted
+			} else {
+				x[k] = h.redactJSON(v)
+			}
+		}
+	case []any:
+		for i, v := range x {
+			x[i] = h.redactJSON(v)
+		}
+	case string:
+		return h.redactString(x)
+	}
+
+	return x
+}
+
+func (h *redactHandler) matchKey(key string) bool {
+	for _, rule := range h.rules {
+		if rule.key != nil && rule.key(key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (h *redactHandler) redactString(s string) string {
+	for _, rule := range h.rules {
+		if rule.value != nil {
+			s = rule.value(s)
+		}
+	}
+
+	return s
+}
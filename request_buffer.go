@@ -0,0 +1,157 @@
+package clog
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+type ctxKeyRequestBuffer struct{}
+
+// ContextWithRequestBuffer returns a new context that buffers log records below the threshold in memory.
+// Buffered records are emitted in order when a record at SeverityError or above is logged with the context,
+// even if they are below the Logger's severity.
+// The returned function discards the remaining records, so call it at the end of the request.
+// If limit is positive, at most limit records are held and the oldest ones are dropped.
+// threshold above SeverityError is clamped to SeverityError so that errors always flush the buffer.
+func ContextWithRequestBuffer(ctx context.Context, threshold Severity, limit int) (context.Context, func()) {
+	b := &requestBuffer{threshold: min(threshold, SeverityError), limit: limit}
+	return context.WithValue(ctx, ctxKeyRequestBuffer{}, b), b.discard
+}
+
+type bufferedRecord struct {
+	ctx     context.Context
+	handler slog.Handler
+	record  slog.Record
+}
+
+type requestBuffer struct {
+	threshold Severity
+	limit     int
+
+	mu      sync.Mutex
+	records []bufferedRecord
+	ended   bool
+}
+
+// add buffers r and reports whether r was buffered.
+func (b *requestBuffer) add(ctx context.Context, h slog.Handler, r slog.Record) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.ended || r.Level >= b.threshold {
+		return false
+	}
+
+	if b.limit > 0 && len(b.records) >= b.limit {
+		b.records = append(b.records[:0], b.records[len(b.records)-b.limit+1:]...)
+	}
+	b.records = append(b.records, bufferedRecord{ctx, h, resolveRecord(r)})
+
+	return true
+}
+
+// resolveRecord returns a copy of r with LogValuers resolved
+// so that buffered records have the values at the time they were logged.
+func resolveRecord(r slog.Record) slog.Record {
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		nr.AddAttrs(resolveAttr(a))
+		return true
+	})
+
+	return nr
+}
+
+func resolveAttr(a slog.Attr) slog.Attr {
+	a.Value = a.Value.Resolve()
+
+	if a.Value.Kind() == slog.KindGroup {
+		group := a.Value.Group()
+		attrs := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			attrs[i] = resolveAttr(ga)
+		}
+		a.Value = slog.GroupValue(attrs...)
+	}
+
+	return a
+}
+
+func (b *requestBuffer) take() []bufferedRecord {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	records := b.records
+	b.records = nil
+
+	return records
+}
+
+func (b *requestBuffer) discard() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.records = nil
+	b.ended = true
+}
+
+func (b *requestBuffer) buffers(level slog.Level) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return !b.ended && level < b.threshold
+}
+
+type requestBufferHandler struct {
+	slog.Handler
+}
+
+func newRequestBufferHandler(h slog.Handler) slog.Handler {
+	return &requestBufferHandler{h}
+}
+
+func (h *requestBufferHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if b, ok := ctx.Value(ctxKeyRequestBuffer{}).(*requestBuffer); ok && b.buffers(level) {
+		return true
+	}
+
+	return h.Handler.Enabled(ctx, level)
+}
+
+func (h *requestBufferHandler) Handle(ctx context.Context, r slog.Record) error {
+	b, ok := ctx.Value(ctxKeyRequestBuffer{}).(*requestBuffer)
+	if !ok {
+		return h.Handler.Handle(ctx, r)
+	}
+
+	if b.add(ctx, h.Handler, r) {
+		return nil
+	}
+
+	if r.Level < SeverityError {
+		// Enabled reports true for levels buffered until the buffer ends,
+		// so records below the Logger's severity may reach here after that.
+		if !h.Handler.Enabled(ctx, r.Level) {
+			return nil
+		}
+
+		return h.Handler.Handle(ctx, r)
+	}
+
+	for _, br := range b.take() {
+		if err := br.handler.Handle(br.ctx, br.record); err != nil {
+			return err
+		}
+	}
+
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *requestBufferHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &requestBufferHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h *requestBufferHandler) WithGroup(group string) slog.Handler {
+	return &requestBufferHandler{h.Handler.WithGroup(group)}
+}
@@ -0,0 +1,110 @@
+package clog_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"go.nownabe.dev/clog"
+	"go.nownabe.dev/clog/errors"
+)
+
+func Test_ContextWithRequestBuffer(t *testing.T) {
+	t.Parallel()
+
+	l, w := newLogger(clog.SeverityInfo)
+
+	ctx, end := clog.ContextWithRequestBuffer(context.Background(), clog.SeverityInfo, 0)
+
+	l.Debug(ctx, "msg1")
+	l.Debug(ctx, "msg2")
+	w.assertLog(t, nil)
+
+	l.Info(ctx, "msg3")
+	w.assertLog(t, buildWantLog("INFO", "msg3"))
+
+	l.Err(ctx, errors.NewWithoutStack("err1"))
+	w.assertLog(t, buildWantLog("DEBUG", "msg1"))
+	w.assertLog(t, buildWantLog("DEBUG", "msg2"))
+	w.assertLog(t, buildWantLog("ERROR", "err1"))
+
+	l.Debug(ctx, "msg4")
+	end()
+	l.Debug(ctx, "msg5")
+	l.Err(ctx, errors.NewWithoutStack("err2"))
+	w.assertLog(t, buildWantLog("ERROR", "err2"))
+	w.assertLog(t, nil)
+}
+
+func Test_ContextWithRequestBuffer_Limit(t *testing.T) {
+	t.Parallel()
+
+	l, w := newLogger(clog.SeverityInfo)
+
+	ctx, end := clog.ContextWithRequestBuffer(context.Background(), clog.SeverityWarning, 2)
+	defer end()
+
+	l.Debug(ctx, "msg1")
+	l.Info(ctx, "msg2")
+	l.Debug(ctx, "msg3")
+	w.assertLog(t, nil)
+
+	l.Err(ctx, errors.NewWithoutStack("err"))
+	w.assertLog(t, buildWantLog("INFO", "msg2"))
+	w.assertLog(t, buildWantLog("DEBUG", "msg3"))
+	w.assertLog(t, buildWantLog("ERROR", "err"))
+}
+
+func Test_ContextWithRequestBuffer_ThresholdAboveError(t *testing.T) {
+	t.Parallel()
+
+	l, w := newLogger(clog.SeverityInfo)
+
+	ctx, end := clog.ContextWithRequestBuffer(context.Background(), clog.SeverityCritical, 0)
+	defer end()
+
+	l.Info(ctx, "msg1")
+	w.assertLog(t, nil)
+
+	l.Err(ctx, errors.NewWithoutStack("err"))
+	w.assertLog(t, buildWantLog("INFO", "msg1"))
+	w.assertLog(t, buildWantLog("ERROR", "err"))
+}
+
+func Test_ContextWithRequestBuffer_Ended(t *testing.T) {
+	t.Parallel()
+
+	l, w := newLogger(clog.SeverityInfo, clog.WithDedup(time.Hour))
+
+	ctx, end := clog.ContextWithRequestBuffer(context.Background(), clog.SeverityInfo, 0)
+
+	// Records retained by handlers outside the buffer are logged after the buffer ends.
+	for i := 0; i < 3; i++ {
+		l.Debug(ctx, "dbg")
+	}
+	end()
+
+	if err := l.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	w.assertLog(t, nil)
+}
+
+func Test_ContextWithRequestBuffer_Resolved(t *testing.T) {
+	t.Parallel()
+
+	l, w := newLogger(clog.SeverityInfo)
+
+	ctx, end := clog.ContextWithRequestBuffer(context.Background(), clog.SeverityInfo, 0)
+	defer end()
+
+	v := "before"
+	l.Debug(ctx, "dbg", "v", clog.Lazy(func() any { return v }),
+		slog.Group("g", "v", clog.Lazy(func() any { return v })))
+	v = "after"
+
+	l.Err(ctx, errors.NewWithoutStack("err"))
+	w.assertLog(t, buildWantLog("DEBUG", "dbg", "v", "before", "g", map[string]any{"v": "before"}))
+	w.assertLog(t, buildWantLog("ERROR", "err"))
+}
@@ -2,8 +2,15 @@ package clog
 
 import (
 	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
 	"runtime"
+	"runtime/debug"
 	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 // sourceLocation represents LogEntrySourceLocation.
@@ -12,33 +19,300 @@ type sourceLocation struct {
 	file     string
 	line     string
 	function string
+
+	// value is built in advance to avoid allocations on each log record.
+	value slog.Value
 }
 
 func (s *sourceLocation) LogValue() slog.Value {
-	return slog.GroupValue(
-		slog.String("file", s.file),
-		slog.String("line", s.line),
-		slog.String("function", s.function),
+	return s.value
+}
+
+// SourcePathMode specifies how file paths in logging.googleapis.com/sourceLocation are emitted.
+type SourcePathMode int
+
+const (
+	// SourcePathFull emits file paths as they are recorded at build time like "/home/runner/work/app/app/main.go".
+	SourcePathFull SourcePathMode = iota
+	// SourcePathImport emits file paths qualified with their import paths like "example.com/app/cmd/server/main.go".
+	SourcePathImport
+	// SourcePathModule emits file paths relative to the main module like "cmd/server/main.go".
+	// Files out of the main module are emitted in the same way as SourcePathImport.
+	SourcePathModule
+	// SourcePathGOPATH emits file paths relative to GOPATH/src, GOROOT/src, or the module cache
+	// like "net/http/server.go" and "example.com/lib@v1.0.0/lib.go".
+	// GOPATH and GOMODCACHE are read from the environment at runtime.
+	// Files out of them are emitted as they are.
+	SourcePathGOPATH
+)
+
+// WithoutSourceLocation returns an Option that disables logging.googleapis.com/sourceLocation.
+// The Logger doesn't call runtime.Callers at all, which is useful for hot paths.
+func WithoutSourceLocation() Option {
+	return loggerOption(func(c *loggerConfig) {
+		c.source.disabled = true
+	})
+}
+
+// WithSourcePath returns an Option that sets how file paths in logging.googleapis.com/sourceLocation are emitted.
+func WithSourcePath(mode SourcePathMode) Option {
+	return loggerOption(func(c *loggerConfig) {
+		c.source.pathMode = mode
+	})
+}
+
+// WithShortFunctionName returns an Option that trims package paths from function names
+// in logging.googleapis.com/sourceLocation like "server.(*Handler).ServeHTTP".
+func WithShortFunctionName() Option {
+	return loggerOption(func(c *loggerConfig) {
+		c.source.shortFunction = true
+	})
+}
+
+type sourceLocationConfig struct {
+	disabled      bool
+	pathMode      SourcePathMode
+	shortFunction bool
+}
+
+func (l *Logger) sourceLocation(skip int) *sourceLocation {
+	if l.cfg.source.disabled {
+		return nil
+	}
+
+	return l.sourceLocationAt(callerPC(skip + l.cfg.callerSkip))
+}
+
+func (l *Logger) sourceLocationAt(pc uintptr) *sourceLocation {
+	if l.cfg.source.disabled || pc == 0 {
+		return nil
+	}
+
+	return sourceLocations.get(pc, l.cfg.source)
+}
+
+// sourceLocations caches source locations by program counters and configurations.
+// Source locations are immutable, so they can be shared between log records.
+var sourceLocations = &sourceLocationCache{m: map[sourceLocationKey]*sourceLocation{}}
+
+type sourceLocationKey struct {
+	pc  uintptr
+	cfg sourceLocationConfig
+}
+
+type sourceLocationCache struct {
+	mu sync.RWMutex
+	m  map[sourceLocationKey]*sourceLocation
+}
+
+func (c *sourceLocationCache) get(pc uintptr, cfg sourceLocationConfig) *sourceLocation {
+	key := sourceLocationKey{pc, cfg}
+
+	c.mu.RLock()
+	src, ok := c.m[key]
+	c.mu.RUnlock()
+
+	if ok {
+		return src
+	}
+
+	f, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	src = newSourceLocation(f, cfg)
+
+	c.mu.Lock()
+	c.m[key] = src
+	c.mu.Unlock()
+
+	return src
+}
+
+func newSourceLocation(f runtime.Frame, cfg sourceLocationConfig) *sourceLocation {
+	src := &sourceLocation{
+		file:     f.File,
+		line:     strconv.Itoa(f.Line),
+		function: f.Function,
+	}
+
+	if cfg.pathMode != SourcePathFull {
+		src.file = trimSourcePath(cfg.pathMode, src.file, src.function)
+	}
+
+	if cfg.shortFunction {
+		src.function = src.function[strings.LastIndexByte(src.function, '/')+1:]
+	}
+
+	src.value = slog.GroupValue(
+		slog.String("file", src.file),
+		slog.String("line", src.line),
+		slog.String("function", src.function),
 	)
+
+	return src
 }
 
-// source returns slog.Source.
+// callerPC returns the program counter of the caller.
 // slog has built-in source mechanism, but it will be wrong when slog is wrapped.
 // cf. https://cs.opensource.google/go/go/+/refs/tags/go1.21.1:src/log/slog/logger.go;l=209
-func getSourceLocation(skip int) *sourceLocation {
-	pcs := make([]uintptr, 1)
+func callerPC(skip int) uintptr {
+	if hasHelpers.Load() {
+		return callerPCSkippingHelpers(skip + 1)
+	}
+
+	var pcs [1]uintptr
+
+	if runtime.Callers(skip, pcs[:]) == 0 {
+		return 0
+	}
+
+	return pcs[0]
+}
+
+func callerPCSkippingHelpers(skip int) uintptr {
+	const maxFrames = 32
+	pcs := make([]uintptr, maxFrames)
 
 	n := runtime.Callers(skip, pcs)
 	if n == 0 {
-		return nil
+		return 0
 	}
 
-	fs := runtime.CallersFrames(pcs)
-	f, _ := fs.Next()
+	fs := runtime.CallersFrames(pcs[:n])
+	for {
+		f, more := fs.Next()
+		if _, ok := helpers.Load(f.Function); !ok || !more {
+			return FramePC(f)
+		}
+	}
+}
 
-	return &sourceLocation{
-		file:     f.File,
-		line:     strconv.Itoa(f.Line),
-		function: f.Function,
+// FramePC returns the program counter to pass to [Logger.LogAt] and its family for the given frame.
+func FramePC(f runtime.Frame) uintptr {
+	// Frame.PC points to the call instruction, while runtime.CallersFrames expects return addresses.
+	return f.PC + 1
+}
+
+var (
+	helpers    sync.Map
+	hasHelpers atomic.Bool
+)
+
+// Helper marks the calling function as a logging helper function.
+// When logging, the function is skipped in logging.googleapis.com/sourceLocation
+// so that wrappers of clog can attribute log entries to their callers.
+// It is similar to testing.T.Helper.
+func Helper() {
+	pcs := make([]uintptr, 1)
+
+	// skip [runtime.Callers, this function]
+	if runtime.Callers(2, pcs) == 0 {
+		return
+	}
+
+	f, _ := runtime.CallersFrames(pcs).Next()
+	if _, loaded := helpers.LoadOrStore(f.Function, struct{}{}); !loaded {
+		hasHelpers.Store(true)
+	}
+}
+
+func trimSourcePath(mode SourcePathMode, file, function string) string {
+	switch mode {
+	case SourcePathImport, SourcePathModule:
+		pkg := packagePath(function)
+		if pkg == "" {
+			return file
+		}
+
+		p := pkg + "/" + file[strings.LastIndexByte(file, '/')+1:]
+		if mode == SourcePathModule {
+			if mod := mainModulePath(); mod != "" && strings.HasPrefix(p, mod+"/") {
+				return p[len(mod)+1:]
+			}
+		}
+
+		return p
+	case SourcePathGOPATH:
+		for _, root := range sourceRoots() {
+			if strings.HasPrefix(file, root) {
+				return file[len(root):]
+			}
+		}
+	case SourcePathFull:
+	}
+
+	return file
+}
+
+// packagePath returns the import path of the package that the function belongs to.
+func packagePath(function string) string {
+	slash := strings.LastIndexByte(function, '/')
+	dot := strings.IndexByte(function[slash+1:], '.')
+	if dot < 0 {
+		return ""
+	}
+
+	pkg := function[:slash+1+dot]
+
+	// Functions in external test packages are named like "example.com/foo_test.TestFoo".
+	pkg = strings.TrimSuffix(pkg, "_test")
+
+	if pkg == "main" {
+		return mainPackagePath()
+	}
+
+	return pkg
+}
+
+// sourceRoots returns GOMODCACHE, GOPATH/src, and GOROOT/src with trailing slashes.
+var sourceRoots = sync.OnceValue(func() []string {
+	var roots []string
+
+	gopath := os.Getenv("GOPATH")
+	if gopath == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			gopath = filepath.Join(home, "go")
+		}
+	}
+	gopaths := filepath.SplitList(gopath)
+
+	modcache := os.Getenv("GOMODCACHE")
+	if modcache == "" && len(gopaths) > 0 {
+		modcache = filepath.Join(gopaths[0], "pkg", "mod")
+	}
+	if modcache != "" {
+		roots = append(roots, filepath.ToSlash(modcache)+"/")
+	}
+
+	for _, p := range gopaths {
+		roots = append(roots, filepath.ToSlash(filepath.Join(p, "src"))+"/")
+	}
+
+	// GOROOT is derived from the file of a standard library function instead of the environment,
+	// because file paths are recorded at build time.
+	if f := runtime.FuncForPC(reflect.ValueOf(strings.Cut).Pointer()); f != nil {
+		file, _ := f.FileLine(f.Entry())
+		if root, ok := strings.CutSuffix(file, "strings/strings.go"); ok {
+			roots = append(roots, root)
+		}
+	}
+
+	return roots
+})
+
+var buildInfo = sync.OnceValue(func() *debug.BuildInfo {
+	bi, _ := debug.ReadBuildInfo()
+	return bi
+})
+
+func mainPackagePath() string {
+	if bi := buildInfo(); bi != nil {
+		return bi.Path
+	}
+	return ""
+}
+
+func mainModulePath() string {
+	if bi := buildInfo(); bi != nil {
+		return bi.Main.Path
 	}
+	return ""
 }
@@ -2,11 +2,16 @@ package clog_test
 
 import (
 	"context"
+	"log/slog"
+	"reflect"
+	"regexp"
 	"runtime"
 	"strconv"
+	"strings"
 	"testing"
 
 	"go.nownabe.dev/clog"
+	"go.nownabe.dev/clog/errors"
 )
 
 func Test_sourceLocation(t *testing.T) {
@@ -29,3 +34,181 @@ func Test_sourceLocation(t *testing.T) {
 
 	w.assertLog(t, want)
 }
+
+func Test_sourceLocation_Options(t *testing.T) {
+	t.Parallel()
+
+	const fn = "go.nownabe.dev/clog_test.Test_sourceLocation_Options.func1"
+
+	tests := map[string]struct {
+		opts []clog.Option
+		want map[string]any
+	}{
+		"without source location": {
+			opts: []clog.Option{clog.WithoutSourceLocation()},
+			want: nil,
+		},
+		"import path": {
+			opts: []clog.Option{clog.WithSourcePath(clog.SourcePathImport)},
+			want: map[string]any{"file": "go.nownabe.dev/clog/source_location_test.go", "line": anyString{}, "function": fn},
+		},
+		"module path": {
+			opts: []clog.Option{clog.WithSourcePath(clog.SourcePathModule)},
+			want: map[string]any{"file": "source_location_test.go", "line": anyString{}, "function": fn},
+		},
+		"short function name": {
+			opts: []clog.Option{clog.WithShortFunctionName()},
+			want: map[string]any{
+				"file":     regexp.MustCompile(`^/.+/source_location_test\.go$`),
+				"line":     anyString{},
+				"function": "clog_test.Test_sourceLocation_Options.func1",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			l, w := newLogger(clog.SeverityInfo, tt.opts...)
+			l.Info(context.Background(), "foo")
+
+			want := buildWantLog("INFO", "foo")
+			if tt.want == nil {
+				delete(want, keySourceLocation)
+			} else {
+				want[keySourceLocation] = tt.want
+			}
+
+			w.assertLog(t, want)
+		})
+	}
+}
+
+func logViaWrapper(ctx context.Context, l *clog.Logger, msg string) {
+	l.WithCallerSkip(1).Info(ctx, msg)
+}
+
+func logViaHelper(ctx context.Context, l *clog.Logger, msg string) {
+	clog.Helper()
+	l.Info(ctx, msg)
+}
+
+func Test_sourceLocation_CallerSkip(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]func(ctx context.Context, l *clog.Logger, msg string){
+		"WithCallerSkip": logViaWrapper,
+		"Helper":         logViaHelper,
+	}
+
+	for name, fn := range tests {
+		fn := fn
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			l, w := newLogger(clog.SeverityInfo)
+			ctx := context.Background()
+
+			pc, _, _, _ := runtime.Caller(0) // This must be called before calling fn
+			fn(ctx, l, "foo")
+
+			frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+
+			want := buildWantLog("INFO", "foo")
+			want[keySourceLocation] = map[string]any{
+				"file":     frame.File,
+				"line":     strconv.Itoa(frame.Line + 1),
+				"function": frame.Function,
+			}
+
+			w.assertLog(t, want)
+		})
+	}
+}
+
+func Test_sourceLocation_At(t *testing.T) {
+	t.Parallel()
+
+	pc, _, _, _ := runtime.Caller(0)
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+
+	wantSrc := map[string]any{
+		"file":     frame.File,
+		"line":     strconv.Itoa(frame.Line),
+		"function": frame.Function,
+	}
+
+	ctx := context.Background()
+
+	tests := map[string]struct {
+		log  func(l *clog.Logger)
+		want map[string]any
+	}{
+		"LogAt": {
+			log:  func(l *clog.Logger) { l.LogAt(ctx, pc, clog.SeverityInfo, "msg", "k", "v") },
+			want: buildWantLog("INFO", "msg", "k", "v"),
+		},
+		"LogAttrsAt": {
+			log:  func(l *clog.Logger) { l.LogAttrsAt(ctx, pc, clog.SeverityNotice, "msg", slog.String("k", "v")) },
+			want: buildWantLog("NOTICE", "msg", "k", "v"),
+		},
+		"ErrAt": {
+			log:  func(l *clog.Logger) { l.ErrAt(ctx, pc, clog.SeverityError, errors.New("err")) },
+			want: buildWantLog("ERROR", "err", "stack_trace", anyString{}),
+		},
+		"FramePC": {
+			log:  func(l *clog.Logger) { l.LogAt(ctx, clog.FramePC(frame), clog.SeverityInfo, "msg") },
+			want: buildWantLog("INFO", "msg"),
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			l, w := newLogger(clog.SeverityInfo)
+			tt.log(l)
+
+			tt.want[keySourceLocation] = wantSrc
+			w.assertLog(t, tt.want)
+		})
+	}
+}
+
+func Test_sourceLocation_GOPATH(t *testing.T) {
+	t.Parallel()
+
+	_, thisFile, _, _ := runtime.Caller(0)
+
+	tests := map[string]struct {
+		pc   uintptr
+		want any
+	}{
+		"GOROOT": {
+			pc:   reflect.ValueOf(strings.ToUpper).Pointer() + 1,
+			want: "strings/strings.go",
+		},
+		// Files out of the roots are emitted as they are even if their paths contain "/src/".
+		"out of roots": {
+			pc:   reflect.ValueOf(Test_sourceLocation_GOPATH).Pointer() + 1,
+			want: thisFile,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			l, w := newLogger(clog.SeverityInfo, clog.WithSourcePath(clog.SourcePathGOPATH))
+			l.LogAt(context.Background(), tt.pc, clog.SeverityInfo, "msg")
+
+			want := buildWantLog("INFO", "msg")
+			want[keySourceLocation] = map[string]any{"file": tt.want, "line": anyString{}, "function": anyString{}}
+			w.assertLog(t, want)
+		})
+	}
+}
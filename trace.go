@@ -46,3 +46,7 @@ func (h *traceHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
 func (h *traceHandler) WithGroup(group string) slog.Handler {
 	return &traceHandler{h.Handler.WithGroup(group), h.projectID}
 }
+
+func (h *traceHandler) Flush(ctx context.Context) error {
+	return flush(ctx, h.Handler)
+}